@@ -0,0 +1,205 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ArgType is the type of a positional argument. It is used to validate the
+// argument before CommandRun.Run is called.
+type ArgType int
+
+// Supported positional argument types.
+const (
+	ArgString ArgType = iota
+	ArgInt
+	ArgFloat
+	ArgBool
+	ArgDuration
+)
+
+// String returns the name of the type as shown in help pages.
+func (t ArgType) String() string {
+	switch t {
+	case ArgString:
+		return "string"
+	case ArgInt:
+		return "int"
+	case ArgFloat:
+		return "float"
+	case ArgBool:
+		return "bool"
+	case ArgDuration:
+		return "duration"
+	default:
+		return fmt.Sprintf("ArgType(%d)", int(t))
+	}
+}
+
+// check returns an error if s cannot be parsed as type t.
+func (t ArgType) check(s string) error {
+	var err error
+	switch t {
+	case ArgInt:
+		_, err = strconv.ParseInt(s, 0, 64)
+	case ArgFloat:
+		_, err = strconv.ParseFloat(s, 64)
+	case ArgBool:
+		_, err = strconv.ParseBool(s)
+	case ArgDuration:
+		_, err = time.ParseDuration(s)
+	}
+	if err != nil {
+		return fmt.Errorf("not a valid %s", t)
+	}
+	return nil
+}
+
+// Arg describes a positional argument of a Command.
+//
+// When a Command declares its arguments, Run validates the arity and the type
+// of the arguments before calling CommandRun.Run, so Run doesn't have to check
+// len(args) itself.
+type Arg struct {
+	// Name is the name of the argument, as shown in help pages.
+	Name string
+	// Desc is a one-line description of the argument.
+	Desc string
+	// Optional is true if the argument can be omitted.
+	Optional bool
+	// Variadic is true if the argument can be repeated. Only the last argument
+	// can be variadic. A required variadic argument must be specified at least
+	// once.
+	Variadic bool
+	// Type is the type of the argument. Defaults to ArgString.
+	Type ArgType
+	// Choices, if set, lists the only values accepted. When Name is empty, the
+	// argument is shown as the list of choices, e.g. "start|stop".
+	Choices []string
+	// Validate is called on each value after the type has been checked. It is
+	// optional.
+	Validate func(value string) error
+	// Complete returns the completion candidates starting with prefix. It is
+	// optional.
+	Complete func(prefix string) []string
+}
+
+// String returns the argument as shown in a usage line, e.g. "[<name>...]".
+func (a Arg) String() string {
+	s := a.label()
+	if a.Variadic {
+		s += "..."
+	}
+	if a.Optional {
+		s = "[" + s + "]"
+	}
+	return s
+}
+
+// label returns the argument's name as shown to the user.
+func (a Arg) label() string {
+	if a.Name == "" {
+		return strings.Join(a.Choices, "|")
+	}
+	return "<" + a.Name + ">"
+}
+
+// check returns an error if value is not valid for this argument.
+func (a Arg) check(value string) error {
+	if err := a.Type.check(value); err != nil {
+		return fmt.Errorf("invalid value %q for argument %s: %w", value, a.label(), err)
+	}
+	if len(a.Choices) != 0 && !slices.Contains(a.Choices, value) {
+		return fmt.Errorf("invalid value %q for argument %s: must be one of %s", value, a.label(), strings.Join(a.Choices, ", "))
+	}
+	if a.Validate != nil {
+		if err := a.Validate(value); err != nil {
+			return fmt.Errorf("invalid value %q for argument %s: %w", value, a.label(), err)
+		}
+	}
+	return nil
+}
+
+// complete returns the completion candidates for this argument.
+func (a Arg) complete(prefix string) []string {
+	if a.Complete != nil {
+		return a.Complete(prefix)
+	}
+	return filterPrefix(a.Choices, prefix)
+}
+
+// assignArgs maps each value to the index of the Arg in specs it is assigned
+// to.
+//
+// Optional arguments are filled in order, as long as enough values are left
+// for the required arguments that follow.
+func assignArgs(specs []Arg, values []string) ([]int, error) {
+	// requiredAfter[i] is the number of values needed by specs[i+1:].
+	requiredAfter := make([]int, len(specs))
+	for i := len(specs) - 2; i >= 0; i-- {
+		requiredAfter[i] = requiredAfter[i+1]
+		if !specs[i+1].Optional {
+			requiredAfter[i]++
+		}
+	}
+	out := make([]int, 0, len(values))
+	for i := range specs {
+		s := &specs[i]
+		remaining := len(values) - len(out)
+		n := 0
+		switch {
+		case s.Variadic:
+			n = remaining - requiredAfter[i]
+		case s.Optional:
+			if remaining > requiredAfter[i] {
+				n = 1
+			}
+		default:
+			n = 1
+		}
+		if n <= 0 && !s.Optional || n > remaining {
+			return nil, fmt.Errorf("missing argument %s", s)
+		}
+		for ; n > 0; n-- {
+			out = append(out, i)
+		}
+	}
+	if len(out) < len(values) {
+		return nil, fmt.Errorf("unexpected argument %q", values[len(out)])
+	}
+	return out, nil
+}
+
+// validateArgs returns an error if values doesn't match specs.
+func validateArgs(specs []Arg, values []string) error {
+	assigned, err := assignArgs(specs, values)
+	if err != nil {
+		return err
+	}
+	for i, v := range values {
+		if err := specs[assigned[i]].check(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// argAt returns the Arg that would receive the positional argument at index i,
+// assuming every optional argument before it is specified. Returns nil if there
+// is none.
+func argAt(specs []Arg, i int) *Arg {
+	for j := range specs {
+		if specs[j].Variadic || i == 0 {
+			return &specs[j]
+		}
+		i--
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func TestArg_String(t *testing.T) {
+	ut.AssertEqual(t, "<a>", Arg{Name: "a"}.String())
+	ut.AssertEqual(t, "[<a>]", Arg{Name: "a", Optional: true}.String())
+	ut.AssertEqual(t, "<a>...", Arg{Name: "a", Variadic: true}.String())
+	ut.AssertEqual(t, "[<a>...]", Arg{Name: "a", Optional: true, Variadic: true}.String())
+}
+
+func TestValidateArgs(t *testing.T) {
+	even := func(s string) error {
+		if i, _ := strconv.Atoi(s); i%2 != 0 {
+			return errors.New("must be even")
+		}
+		return nil
+	}
+	data := []struct {
+		specs  []Arg
+		values []string
+		err    string
+	}{
+		{nil, nil, ""},
+		{nil, []string{"a"}, "unexpected argument \"a\""},
+		{[]Arg{{Name: "a"}}, nil, "missing argument <a>"},
+		{[]Arg{{Name: "a"}}, []string{"x"}, ""},
+		{[]Arg{{Name: "a"}}, []string{"x", "y"}, "unexpected argument \"y\""},
+		{[]Arg{{Name: "a", Optional: true}, {Name: "b"}}, []string{"x"}, ""},
+		{[]Arg{{Name: "a", Optional: true}, {Name: "b"}}, []string{"x", "y"}, ""},
+		{[]Arg{{Name: "a", Variadic: true}}, nil, "missing argument <a>..."},
+		{[]Arg{{Name: "a", Variadic: true}}, []string{"x", "y", "z"}, ""},
+		{[]Arg{{Name: "a", Optional: true, Variadic: true}}, nil, ""},
+		{[]Arg{{Name: "a"}, {Name: "b", Variadic: true}, {Name: "c"}}, []string{"x", "y"}, "missing argument <b>..."},
+		{[]Arg{{Name: "n", Type: ArgInt}}, []string{"12"}, ""},
+		{[]Arg{{Name: "n", Type: ArgInt}}, []string{"twelve"}, "invalid value \"twelve\" for argument <n>: not a valid int"},
+		{[]Arg{{Name: "d", Type: ArgDuration}}, []string{"1s"}, ""},
+		{[]Arg{{Name: "n", Type: ArgInt, Validate: even}}, []string{"3"}, "invalid value \"3\" for argument <n>: must be even"},
+	}
+	for i, line := range data {
+		err := validateArgs(line.specs, line.values)
+		if line.err == "" {
+			ut.AssertEqualIndex(t, i, nil, err)
+		} else {
+			ut.AssertEqualIndex(t, i, line.err, err.Error())
+		}
+	}
+}
+
+func TestRunArgs(t *testing.T) {
+	data := []struct {
+		args []string
+		err  string
+		exit int
+	}{
+		{[]string{"count", "1"}, "", 42},
+		{[]string{"count"}, "App: missing argument <n>\n\nRun 'App help count' for usage.\n", 2},
+		{[]string{"count", "a"}, "App: invalid value \"a\" for argument <n>: not a valid int\n\nRun 'App help count' for usage.\n", 2},
+		{[]string{"help", "count"}, "usage:  App count <n> [<names>...]\n  <n> int\n    \tNumber of times.\n  [<names>...]\n", 0},
+	}
+	for i, line := range data {
+		line := line
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			a := application{
+				DefaultApplication: DefaultApplication{
+					Name: "App",
+					Commands: []*Command{
+						CmdHelp,
+						{
+							UsageLine: "count <n> [<names>...]",
+							Args: []Arg{
+								{Name: "n", Desc: "Number of times.", Type: ArgInt},
+								{Name: "names", Optional: true, Variadic: true},
+							},
+							CommandRun: func() CommandRun {
+								return &command{}
+							},
+						},
+					},
+				},
+			}
+			ut.AssertEqual(t, line.exit, Run(&a, line.args))
+			ut.AssertEqual(t, line.err, a.err.String())
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"flag"
+	"io"
+	"strings"
+)
+
+// Complete returns the completion candidates for the last element of args.
+//
+// args is the command line without the application name, the last element
+// being the word being completed, possibly empty. It completes command names,
+// flag names, flag values implementing Completer and the positional arguments
+// declared in Command.Args or derived from Command.UsageLine.
+//
+// This is meant to be called by a shell completion script.
+func Complete(a Application, args []string) []string {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	word := args[len(args)-1]
+	if len(args) == 1 {
+		var out []string
+		for _, c := range a.GetCommands() {
+			if !c.isSection && strings.HasPrefix(c.Name(), word) {
+				out = append(out, c.Name())
+			}
+		}
+		return out
+	}
+	c := FindCommand(a, args[0])
+	if c == nil {
+		return nil
+	}
+	c = c.described()
+	if c.CommandRun == nil {
+		return nil
+	}
+	words := args[1 : len(args)-1]
+	f := c.CommandRun().GetFlags()
+	if f != nil {
+		f.Init(c.Name(), flag.ContinueOnError)
+		f.SetOutput(io.Discard)
+		f.Usage = func() {}
+		if strings.HasPrefix(word, "-") {
+			if name, value, ok := strings.Cut(word, "="); ok {
+				// Parse the flags already specified, so flag values like Strings
+				// skip the values already given.
+				_ = f.Parse(words)
+				var out []string
+				for _, v := range completeFlagValue(f, name, value) {
+					out = append(out, name+"="+v)
+				}
+				return out
+			}
+			return completeFlags(c, f, word)
+		}
+		if len(words) != 0 {
+			if prev := words[len(words)-1]; strings.HasPrefix(prev, "-") && !strings.Contains(prev, "=") {
+				if fl := f.Lookup(strings.TrimLeft(prev, "-")); fl != nil && !isBoolFlag(fl) {
+					_ = f.Parse(words[:len(words)-1])
+					return completeFlagValue(f, prev, word)
+				}
+			}
+		}
+		if err := f.Parse(words); err != nil {
+			return nil
+		}
+		words = f.Args()
+	}
+	specs, _ := c.args()
+	if arg := argAt(specs, len(words)); arg != nil {
+		return arg.complete(word)
+	}
+	return nil
+}
+
+// completeFlagValue returns the completion candidates for the value of the
+// flag named name, as specified on the command line, e.g. "-name".
+func completeFlagValue(f *flag.FlagSet, name, prefix string) []string {
+	fl := f.Lookup(strings.TrimLeft(name, "-"))
+	if fl == nil {
+		return nil
+	}
+	if c, ok := fl.Value.(Completer); ok {
+		return c.Complete(prefix)
+	}
+	return nil
+}
+
+// isBoolFlag returns true if the flag doesn't require a value.
+func isBoolFlag(fl *flag.Flag) bool {
+	b, ok := fl.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// completeFlags returns the flags defined in f that start with word,
+// excluding deprecated flags.
+func completeFlags(c *Command, f *flag.FlagSet, word string) []string {
+	dashes := "-"
+	if strings.HasPrefix(word, "--") {
+		dashes = "--"
+	}
+	prefix := strings.TrimPrefix(word, dashes)
+	var out []string
+	f.VisitAll(func(fl *flag.Flag) {
+		if _, ok := c.DeprecatedFlags[fl.Name]; !ok && strings.HasPrefix(fl.Name, prefix) {
+			out = append(out, dashes+fl.Name)
+		}
+	})
+	return out
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func TestComplete(t *testing.T) {
+	colors := []string{"blue", "green", "red"}
+	a := &DefaultApplication{
+		Commands: []*Command{
+			Section("Main"),
+			{
+				UsageLine: "paint <color>",
+				Args: []Arg{
+					{
+						Name: "color",
+						Complete: func(prefix string) []string {
+							var out []string
+							for _, c := range colors {
+								if strings.HasPrefix(c, prefix) {
+									out = append(out, c)
+								}
+							}
+							return out
+						},
+					},
+				},
+				CommandRun: func() CommandRun {
+					c := &command{}
+					c.Flags.Bool("glossy", false, "")
+					c.Flags.String("brush", "", "")
+					return c
+				},
+			},
+			{UsageLine: "peel"},
+		},
+	}
+	ut.AssertEqual(t, []string{"paint", "peel"}, Complete(a, nil))
+	ut.AssertEqual(t, []string{"paint"}, Complete(a, []string{"pa"}))
+	ut.AssertEqual(t, []string{"-brush", "-glossy"}, Complete(a, []string{"paint", "-"}))
+	ut.AssertEqual(t, []string{"--glossy"}, Complete(a, []string{"paint", "--g"}))
+	ut.AssertEqual(t, colors, Complete(a, []string{"paint", ""}))
+	ut.AssertEqual(t, []string{"green"}, Complete(a, []string{"paint", "-glossy", "-brush", "big", "g"}))
+	ut.AssertEqual(t, []string(nil), Complete(a, []string{"paint", "red", ""}))
+	ut.AssertEqual(t, []string(nil), Complete(a, []string{"inexistant", ""}))
+}
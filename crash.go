@@ -0,0 +1,110 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// PanicExitCode is the exit code returned when a panic is caught by the
+// middleware returned by RecoverPanics. It is EX_SOFTWARE from sysexits.h.
+const PanicExitCode = 70
+
+// RecoverPanics returns a Middleware that recovers panics in commands.
+//
+// Instead of the goroutine trace, a one-line message is printed to GetErr()
+// and a crash report is written in dir, or os.TempDir() if dir is empty. The
+// report contains the command, the command line with the values redacted, the
+// Go version, the build information and the stack trace. The exit code is
+// PanicExitCode.
+//
+// It should be the first middleware so it covers the other ones.
+func RecoverPanics(dir string) Middleware {
+	return func(next Handler) Handler {
+		return func(inv *Invocation) (exitCode int) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				exitCode = PanicExitCode
+				stack := debug.Stack()
+				a := inv.App
+				fmt.Fprintf(a.GetErr(), "%s internal error: %v\n", errorPrefix(a), v)
+				p, err := writeCrashReport(dir, inv, v, stack)
+				if err != nil {
+					fmt.Fprintf(a.GetErr(), "%s failed to write crash report: %s\n", errorPrefix(a), err)
+					return
+				}
+				fmt.Fprintf(a.GetErr(), "A crash report was written to %s\n", p)
+			}()
+			return next(inv)
+		}
+	}
+}
+
+// writeCrashReport writes the crash report and returns its path.
+func writeCrashReport(dir string, inv *Invocation, v interface{}, stack []byte) (string, error) {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	name := strings.NewReplacer(" ", "-", "/", "-", string(filepath.Separator), "-").Replace(inv.App.GetName())
+	if name == "" {
+		name = "subcommands"
+	}
+	b := bytes.Buffer{}
+	fmt.Fprintf(&b, "command: %s %s\n", inv.App.GetName(), inv.Command.Name())
+	fmt.Fprintf(&b, "args: %s\n", strings.Join(redactedArgs(inv), " "))
+	fmt.Fprintf(&b, "panic: %v\n", v)
+	fmt.Fprintf(&b, "time: %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(&b, "\nbuild info:\n%s", bi)
+	}
+	fmt.Fprintf(&b, "\nstack:\n%s", stack)
+	// CreateTemp makes the name unique, even for multiple crashes in the same
+	// second.
+	f, err := os.CreateTemp(dir, name+"-crash-"+now.Format("20060102-150405")+"-*.txt")
+	if err != nil {
+		return "", err
+	}
+	if _, err = f.Write(b.Bytes()); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// redactedArgs returns the command line of inv with the values of non-boolean
+// flags and the positional arguments redacted, since they may contain
+// secrets.
+func redactedArgs(inv *Invocation) []string {
+	var out []string
+	if f := inv.CommandRun.GetFlags(); f != nil {
+		f.Visit(func(fl *flag.Flag) {
+			if isBoolFlag(fl) {
+				out = append(out, "-"+fl.Name+"="+fl.Value.String())
+			} else {
+				out = append(out, "-"+fl.Name+"=REDACTED")
+			}
+		})
+	}
+	for range inv.Args {
+		out = append(out, "REDACTED")
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+type panicRun struct {
+	CommandRunBase
+}
+
+func (p *panicRun) Run(a Application, args []string, env Env) int {
+	panic("oh no")
+}
+
+func TestRecoverPanics(t *testing.T) {
+	dir := t.TempDir()
+	a := &application{
+		DefaultApplication: DefaultApplication{
+			Name:        "App",
+			Middlewares: []Middleware{RecoverPanics(dir)},
+			Commands: []*Command{
+				{
+					UsageLine: "boom [<secret>]",
+					CommandRun: func() CommandRun {
+						c := &panicRun{}
+						c.Flags.Bool("v", false, "")
+						c.Flags.String("password", "", "")
+						return c
+					},
+				},
+			},
+		},
+	}
+	ut.AssertEqual(t, PanicExitCode, Run(a, []string{"boom", "-v", "-password", "hunter2", "s3cr3t"}))
+	files, err := filepath.Glob(filepath.Join(dir, "App-crash-*.txt"))
+	ut.AssertEqual(t, nil, err)
+	ut.AssertEqual(t, 1, len(files))
+	ut.AssertEqual(t, "App: internal error: oh no\nA crash report was written to "+files[0]+"\n", a.err.String())
+	report, err := os.ReadFile(files[0])
+	ut.AssertEqual(t, nil, err)
+	s := string(report)
+	ut.AssertEqual(t, true, strings.HasPrefix(s, "command: App boom\nargs: -password=REDACTED -v=true REDACTED\npanic: oh no\n"))
+	ut.AssertEqual(t, true, strings.Contains(s, runtime.Version()))
+	ut.AssertEqual(t, true, strings.Contains(s, "panicRun"))
+	ut.AssertEqual(t, false, strings.Contains(s, "hunter2"))
+	ut.AssertEqual(t, false, strings.Contains(s, "s3cr3t"))
+}
+
+func TestRecoverPanics_SameSecond(t *testing.T) {
+	dir := t.TempDir()
+	a := &application{DefaultApplication: DefaultApplication{Name: "App/sub"}}
+	inv := &Invocation{App: a, Command: &Command{UsageLine: "boom"}, CommandRun: &panicRun{}}
+	p1, err := writeCrashReport(dir, inv, "a", nil)
+	ut.AssertEqual(t, nil, err)
+	p2, err := writeCrashReport(dir, inv, "b", nil)
+	ut.AssertEqual(t, nil, err)
+	ut.AssertEqual(t, true, p1 != p2)
+	ut.AssertEqual(t, dir, filepath.Dir(p1))
+	ut.AssertEqual(t, true, strings.HasPrefix(filepath.Base(p1), "App-sub-crash-"))
+}
@@ -0,0 +1,212 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Example is an example of a command line.
+type Example struct {
+	// Command is the command line without the application's name, e.g.
+	// "greet -v bob". Arguments containing spaces can be quoted.
+	Command string
+	// Desc explains what the example does.
+	Desc string
+	// ExitCode is the expected exit code when the example is verified with
+	// subcommandstest.CheckExamples.
+	ExitCode int
+}
+
+// commandDoc is the documentation of a command, as used by WriteMarkdown and
+// WriteMan.
+type commandDoc struct {
+	cmd   *Command
+	args  []Arg
+	flags string
+}
+
+// commandDocs returns the documentation of all the commands of a, including
+// the advanced ones. Sections are skipped.
+func commandDocs(a Application) []commandDoc {
+	var out []commandDoc
+	cmds := loadCommands(a.GetCommands()...)
+	for _, c := range cmds {
+		if c.isSection {
+			continue
+		}
+		d := commandDoc{cmd: c}
+		d.args, _ = c.args()
+		if c.CommandRun != nil {
+			if f := c.CommandRun().GetFlags(); f != nil {
+				b := strings.Builder{}
+				printDefaults(&b, nil, c, f, true)
+				d.flags = b.String()
+			}
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// sortedEnvVars returns the environment variables of a and its commands sorted
+// by name.
+func sortedEnvVars(a Application) []UsageEnvVar {
+	m := allEnvVars(a)
+	out := make([]UsageEnvVar, 0, len(m))
+	for k, v := range m {
+		out = append(out, UsageEnvVar{k, v.ShortDesc, v.Default})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// WriteMarkdown writes the documentation of a formatted as markdown, including
+// the advanced commands and flags.
+func WriteMarkdown(w io.Writer, a Application) error {
+	b := strings.Builder{}
+	name := a.GetName()
+	fmt.Fprintf(&b, "# %s\n\n", name)
+	if t := a.GetTitle(); t != "" {
+		fmt.Fprintf(&b, "%s\n\n", t)
+	}
+	fmt.Fprintf(&b, "## Usage\n\n```\n%s [command] [arguments]\n```\n\n## Commands\n\n", name)
+	for _, d := range commandDocs(a) {
+		fmt.Fprintf(&b, "### %s\n\n", d.cmd.Name())
+		if d.cmd.ShortDesc != "" {
+			fmt.Fprintf(&b, "%s", d.cmd.ShortDesc)
+			if d.cmd.Advanced {
+				b.WriteString(" (advanced)")
+			}
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "```\n%s %s\n```\n\n", name, d.cmd.UsageLine)
+		if len(d.cmd.Aliases) != 0 {
+			fmt.Fprintf(&b, "Aliases: `%s`\n\n", strings.Join(d.cmd.Aliases, "`, `"))
+		}
+		if l := strings.TrimSpace(d.cmd.LongDesc); l != "" {
+			fmt.Fprintf(&b, "%s\n\n", l)
+		}
+		if len(d.args) != 0 {
+			b.WriteString("Arguments:\n\n")
+			for _, arg := range d.args {
+				fmt.Fprintf(&b, "- `%s`", arg)
+				if arg.Desc != "" {
+					fmt.Fprintf(&b, ": %s", arg.Desc)
+				}
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+		if d.flags != "" {
+			fmt.Fprintf(&b, "Flags:\n\n```\n%s```\n\n", d.flags)
+		}
+		if len(d.cmd.Examples) != 0 {
+			b.WriteString("Examples:\n\n")
+			for _, e := range d.cmd.Examples {
+				if e.Desc != "" {
+					fmt.Fprintf(&b, "%s\n\n", e.Desc)
+				}
+				fmt.Fprintf(&b, "```\n%s %s\n```\n\n", name, e.Command)
+			}
+		}
+	}
+	if topics := getHelpTopics(a); len(topics) != 0 {
+		b.WriteString("## Help Topics\n\n")
+		for _, t := range topics {
+			fmt.Fprintf(&b, "### %s\n\n%s\n\n", t.Name, topicDesc(t))
+		}
+	}
+	if envVars := sortedEnvVars(a); len(envVars) != 0 {
+		b.WriteString("## Environment Variables\n\n")
+		for _, e := range envVars {
+			fmt.Fprintf(&b, "- `%s`: %s\n", e.Name, e.Desc())
+		}
+	}
+	_, err := io.WriteString(w, strings.TrimRight(b.String(), "\n")+"\n")
+	return err
+}
+
+// WriteMan writes the documentation of a formatted as a man page in section 1,
+// including the advanced commands and flags.
+func WriteMan(w io.Writer, a Application) error {
+	b := strings.Builder{}
+	name := a.GetName()
+	fmt.Fprintf(&b, ".TH %s 1\n.SH NAME\n%s", manEscape(strings.ToUpper(name)), manEscape(name))
+	if t := a.GetTitle(); t != "" {
+		fmt.Fprintf(&b, " \\- %s", manEscape(t))
+	}
+	fmt.Fprintf(&b, "\n.SH SYNOPSIS\n.B %s\n[command] [arguments]\n.SH COMMANDS\n", manEscape(name))
+	for _, d := range commandDocs(a) {
+		fmt.Fprintf(&b, ".SS %s\n", manEscape(d.cmd.Name()))
+		fmt.Fprintf(&b, ".B %s %s\n", manEscape(name), manEscape(d.cmd.Name()))
+		if rest := strings.TrimSpace(strings.TrimPrefix(d.cmd.UsageLine, d.cmd.Name())); rest != "" {
+			fmt.Fprintf(&b, "%s\n", manEscape(rest))
+		}
+		desc := strings.TrimSpace(d.cmd.LongDesc)
+		if desc == "" {
+			desc = d.cmd.ShortDesc
+		}
+		if desc != "" {
+			fmt.Fprintf(&b, ".PP\n%s\n", manEscape(desc))
+		}
+		if len(d.cmd.Aliases) != 0 {
+			fmt.Fprintf(&b, ".PP\nAliases: %s\n", manEscape(strings.Join(d.cmd.Aliases, ", ")))
+		}
+		for _, arg := range d.args {
+			fmt.Fprintf(&b, ".TP\n.I %s\n%s\n", manEscape(arg.String()), manEscape(arg.Desc))
+		}
+		if d.flags != "" {
+			fmt.Fprintf(&b, ".PP\nFlags:\n.RS\n.nf\n%s.fi\n.RE\n", manEscape(d.flags))
+		}
+		if len(d.cmd.Examples) != 0 {
+			b.WriteString(".PP\nExamples:\n")
+		}
+		for _, e := range d.cmd.Examples {
+			if e.Desc != "" {
+				fmt.Fprintf(&b, ".PP\n%s\n", manEscape(e.Desc))
+			}
+			fmt.Fprintf(&b, ".RS\n.nf\n%s %s\n.fi\n.RE\n", manEscape(name), manEscape(e.Command))
+		}
+	}
+	if topics := getHelpTopics(a); len(topics) != 0 {
+		b.WriteString(".SH TOPICS\n")
+		for _, t := range topics {
+			fmt.Fprintf(&b, ".SS %s\n%s\n", manEscape(t.Name), manEscape(topicDesc(t)))
+		}
+	}
+	if envVars := sortedEnvVars(a); len(envVars) != 0 {
+		b.WriteString(".SH ENVIRONMENT\n")
+		for _, e := range envVars {
+			fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", manEscape(e.Name), manEscape(e.Desc()))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// topicDesc returns the description of t, preferring LongDesc.
+func topicDesc(t *HelpTopic) string {
+	if l := strings.TrimSpace(t.LongDesc); l != "" {
+		return l
+	}
+	return t.ShortDesc
+}
+
+// manEscape escapes s for use in a roff document.
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\e")
+	s = strings.ReplaceAll(s, "-", "\\-")
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, ".") || strings.HasPrefix(l, "'") {
+			lines[i] = "\\&" + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func docsApp() *application {
+	return &application{
+		DefaultApplication: DefaultApplication{
+			Name:  "App",
+			Title: "Does things.",
+			Commands: []*Command{
+				Section("Main"),
+				{
+					UsageLine: "greet [-loud] <who>",
+					ShortDesc: "greets someone",
+					LongDesc:  "Greets someone.\n.Really.",
+					Args:      []Arg{{Name: "who", Desc: "Person to greet."}},
+					Examples: []Example{
+						{Command: "greet bob", Desc: "Greets bob."},
+						{Command: "greet -loud bob"},
+					},
+					CommandRun: func() CommandRun {
+						c := &command{}
+						c.Flags.Bool("loud", false, "Greet loudly")
+						return c
+					},
+				},
+				{UsageLine: "secret", ShortDesc: "does secret things", Advanced: true, FreeForm: true},
+			},
+			EnvVars: map[string]EnvVarDefinition{
+				"APP_HOME": {ShortDesc: "Home directory.", Default: `C:\app`},
+			},
+		},
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	buf := bytes.Buffer{}
+	ut.AssertEqual(t, nil, WriteMarkdown(&buf, docsApp()))
+	expected := "# App\n" +
+		"\n" +
+		"Does things.\n" +
+		"\n" +
+		"## Usage\n" +
+		"\n" +
+		"```\n" +
+		"App [command] [arguments]\n" +
+		"```\n" +
+		"\n" +
+		"## Commands\n" +
+		"\n" +
+		"### greet\n" +
+		"\n" +
+		"greets someone\n" +
+		"\n" +
+		"```\n" +
+		"App greet [-loud] <who>\n" +
+		"```\n" +
+		"\n" +
+		"Greets someone.\n" +
+		".Really.\n" +
+		"\n" +
+		"Arguments:\n" +
+		"\n" +
+		"- `<who>`: Person to greet.\n" +
+		"\n" +
+		"Flags:\n" +
+		"\n" +
+		"```\n" +
+		"  -loud\n" +
+		"    \tGreet loudly\n" +
+		"```\n" +
+		"\n" +
+		"Examples:\n" +
+		"\n" +
+		"Greets bob.\n" +
+		"\n" +
+		"```\n" +
+		"App greet bob\n" +
+		"```\n" +
+		"\n" +
+		"```\n" +
+		"App greet -loud bob\n" +
+		"```\n" +
+		"\n" +
+		"### secret\n" +
+		"\n" +
+		"does secret things (advanced)\n" +
+		"\n" +
+		"```\n" +
+		"App secret\n" +
+		"```\n" +
+		"\n" +
+		"## Environment Variables\n" +
+		"\n" +
+		"- `APP_HOME`: Home directory. (Default: \"C:\\\\app\")\n"
+	ut.AssertEqual(t, expected, buf.String())
+}
+
+func TestWriteMan(t *testing.T) {
+	buf := bytes.Buffer{}
+	ut.AssertEqual(t, nil, WriteMan(&buf, docsApp()))
+	expected := ".TH APP 1\n" +
+		".SH NAME\n" +
+		"App \\- Does things.\n" +
+		".SH SYNOPSIS\n" +
+		".B App\n" +
+		"[command] [arguments]\n" +
+		".SH COMMANDS\n" +
+		".SS greet\n" +
+		".B App greet\n" +
+		"[\\-loud] <who>\n" +
+		".PP\n" +
+		"Greets someone.\n" +
+		"\\&.Really.\n" +
+		".TP\n" +
+		".I <who>\n" +
+		"Person to greet.\n" +
+		".PP\n" +
+		"Flags:\n" +
+		".RS\n" +
+		".nf\n" +
+		"  \\-loud\n" +
+		"    \tGreet loudly\n" +
+		".fi\n" +
+		".RE\n" +
+		".PP\n" +
+		"Examples:\n" +
+		".PP\n" +
+		"Greets bob.\n" +
+		".RS\n" +
+		".nf\n" +
+		"App greet bob\n" +
+		".fi\n" +
+		".RE\n" +
+		".RS\n" +
+		".nf\n" +
+		"App greet \\-loud bob\n" +
+		".fi\n" +
+		".RE\n" +
+		".SS secret\n" +
+		".B App secret\n" +
+		".PP\n" +
+		"does secret things\n" +
+		".SH ENVIRONMENT\n" +
+		".TP\n" +
+		".B APP_HOME\n" +
+		"Home directory. (Default: \"C:\\e\\eapp\")\n"
+	ut.AssertEqual(t, expected, buf.String())
+}
+
+func TestCommandHelp_Examples(t *testing.T) {
+	a := docsApp()
+	a.Commands = append(a.Commands, CmdHelp)
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "greet"}))
+	expected := "Greets someone.\n.Really.\n\n" +
+		"usage:  App greet [-loud] <who>\n" +
+		"  <who>\n" +
+		"    \tPerson to greet.\n" +
+		"  -loud\n" +
+		"    \tGreet loudly\n" +
+		"\n" +
+		"examples:\n" +
+		"  App greet bob\n" +
+		"    Greets bob.\n" +
+		"  App greet -loud bob\n"
+	ut.AssertEqual(t, expected, a.err.String())
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/texttheater/golang-levenshtein/levenshtein"
+)
+
+// EnvVarCheck enables warnings about environment variables that look like
+// they are meant for the application but are not declared in GetEnvVars or
+// Command.EnvVars, for example GREET_STYL instead of GREET_STYLE.
+//
+// Only the variables with one of the application's prefixes are checked, so
+// unrelated variables like CI or HOST are never reported.
+type EnvVarCheck struct {
+	// Prefixes are the prefixes of the application's environment variables,
+	// e.g. "GREET_". Undeclared variables with one of these prefixes are
+	// reported. When nil, the prefix is the application's name in upper case,
+	// with characters other than letters and digits replaced by underscores,
+	// followed by an underscore, e.g. "MY_TOOL_" for "my-tool".
+	Prefixes []string
+	// MaxDistance is the maximum Levenshtein distance, where a substitution
+	// costs 2, between an undeclared variable and a declared one for the
+	// declared variable to be suggested. When 0, it is 3. The distance is also
+	// limited to a third of the undeclared variable's length, so short names
+	// don't get unrelated suggestions.
+	MaxDistance int
+}
+
+// EnvVarCheckApplication is implemented by an Application that checks the
+// environment for unknown variables before running a command. The check is
+// disabled when GetEnvVarCheck returns nil.
+type EnvVarCheckApplication interface {
+	Application
+	GetEnvVarCheck() *EnvVarCheck
+}
+
+// UnknownEnvVar is an environment variable reported by UnknownEnvVars.
+type UnknownEnvVar struct {
+	Name string
+	// Suggestion is the nearest declared environment variable, if any.
+	Suggestion string
+}
+
+// UnknownEnvVars returns the variables in environ, formatted as returned by
+// os.Environ, that are not declared by a but have one of the prefixes in
+// check. They are sorted by name.
+func UnknownEnvVars(a Application, check *EnvVarCheck, environ []string) []UnknownEnvVar {
+	declared := allEnvVars(a)
+	if len(declared) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(declared))
+	for k := range declared {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	prefixes := check.Prefixes
+	if prefixes == nil {
+		if p := envVarPrefix(a.GetName()); p != "" {
+			prefixes = []string{p}
+		}
+	}
+	maxD := check.MaxDistance
+	if maxD == 0 {
+		maxD = maxSuggestionDistance
+	}
+	var out []UnknownEnvVar
+	for _, kv := range environ {
+		// On Windows, variables like "=C:" start with '='.
+		i := strings.IndexByte(kv[min(1, len(kv)):], '=') + 1
+		if i <= 0 {
+			continue
+		}
+		name := kv[:i]
+		if _, ok := declared[name]; ok {
+			continue
+		}
+		if !slices.ContainsFunc(prefixes, func(p string) bool { return strings.HasPrefix(name, p) }) {
+			continue
+		}
+		closest, closestD := "", min(maxD, len(name)/3)+1
+		for _, n := range names {
+			if d := levenshtein.DistanceForStrings([]rune(n), []rune(name), levenshtein.DefaultOptions); d < closestD {
+				closest, closestD = n, d
+			}
+		}
+		out = append(out, UnknownEnvVar{name, closest})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// envVarPrefix returns the default prefix of the environment variables of the
+// application named name, or "" if name is empty.
+func envVarPrefix(name string) string {
+	if name == "" {
+		return ""
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name) + "_"
+}
+
+// warnUnknownEnvVars prints a warning for each variable returned by
+// UnknownEnvVars, if the application enabled the check.
+func warnUnknownEnvVars(a Application) {
+	e, ok := AppAs[EnvVarCheckApplication](a)
+	if !ok {
+		return
+	}
+	check := e.GetEnvVarCheck()
+	if check == nil {
+		return
+	}
+	for _, u := range UnknownEnvVars(a, check, os.Environ()) {
+		if u.Suggestion != "" {
+			fmt.Fprintf(a.GetErr(), "%s environment variable %s is unknown, did you mean %s?\n", errorPrefix(a), u.Name, u.Suggestion)
+		} else {
+			fmt.Fprintf(a.GetErr(), "%s environment variable %s is unknown\n", errorPrefix(a), u.Name)
+		}
+	}
+}
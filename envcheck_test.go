@@ -0,0 +1,135 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func TestUnknownEnvVars(t *testing.T) {
+	a := &DefaultApplication{
+		Name: "greet",
+		EnvVars: map[string]EnvVarDefinition{
+			"GREET_STYLE":   {},
+			"GREET_COLOR":   {},
+			"VERBOSE":       {},
+			"OTHER_TIMEOUT": {},
+			"CC":            {},
+			"HOSTNAME":      {},
+		},
+	}
+	environ := []string{
+		"=C:=C:\\",
+		"PATH=/bin",
+		"HOME=/root",
+		"HOST=h",
+		"CI=1",
+		"GREET_STYLE=Hi",
+		"GREET_STYL=Hello",
+		"GREET_COLOUR=red",
+		"GREET_SOMETHING_ELSE=1",
+		"VERBSE=1",
+		"OTHER_THING=1",
+		"GREETSTYLE=1",
+		"XDG_RUNTIME_DIR=/run",
+		"GIT_DIR=.git",
+		"empty",
+	}
+	data := []struct {
+		check    EnvVarCheck
+		expected []UnknownEnvVar
+	}{
+		{
+			EnvVarCheck{},
+			[]UnknownEnvVar{
+				{"GREET_COLOUR", "GREET_COLOR"},
+				{"GREET_SOMETHING_ELSE", ""},
+				{"GREET_STYL", "GREET_STYLE"},
+			},
+		},
+		{
+			EnvVarCheck{Prefixes: []string{"OTHER_"}, MaxDistance: 1},
+			[]UnknownEnvVar{{"OTHER_THING", ""}},
+		},
+		{
+			EnvVarCheck{Prefixes: []string{"GREET_", "VERB"}, MaxDistance: 1},
+			[]UnknownEnvVar{
+				{"GREET_COLOUR", "GREET_COLOR"},
+				{"GREET_SOMETHING_ELSE", ""},
+				{"GREET_STYL", "GREET_STYLE"},
+				{"VERBSE", "VERBOSE"},
+			},
+		},
+		{
+			EnvVarCheck{Prefixes: []string{"H", "C"}},
+			[]UnknownEnvVar{{"CI", ""}, {"HOME", ""}, {"HOST", ""}},
+		},
+		{
+			EnvVarCheck{Prefixes: []string{}},
+			nil,
+		},
+	}
+	for i, line := range data {
+		ut.AssertEqualIndex(t, i, line.expected, UnknownEnvVars(a, &line.check, environ))
+	}
+	ut.AssertEqual(t, []UnknownEnvVar(nil), UnknownEnvVars(&DefaultApplication{}, &EnvVarCheck{}, environ))
+}
+
+func TestEnvVarPrefix(t *testing.T) {
+	ut.AssertEqual(t, "", envVarPrefix(""))
+	ut.AssertEqual(t, "GREET_", envVarPrefix("greet"))
+	ut.AssertEqual(t, "SAMPLE_COMPLEX_", envVarPrefix("sample-complex"))
+	ut.AssertEqual(t, "TOOL2_", envVarPrefix("Tool2"))
+}
+
+func TestRunUnknownEnvVars(t *testing.T) {
+	t.Setenv("APP_STYL", "Hello")
+	t.Setenv("GREET_STYL", "Hello")
+	data := []struct {
+		check *EnvVarCheck
+		err   string
+	}{
+		{nil, ""},
+		{&EnvVarCheck{}, "App: environment variable APP_STYL is unknown, did you mean APP_STYLE?\n"},
+	}
+	for i, line := range data {
+		a := &application{
+			DefaultApplication: DefaultApplication{
+				Name:        "App",
+				EnvVarCheck: line.check,
+				EnvVars:     map[string]EnvVarDefinition{"APP_STYLE": {}, "GREET_STYLE": {}},
+				Commands: []*Command{
+					{UsageLine: "status", CommandRun: func() CommandRun { return &command{} }},
+				},
+			},
+		}
+		ut.AssertEqualIndex(t, i, 42, Run(a, []string{"status"}))
+		ut.AssertEqualIndex(t, i, line.err, a.err.String())
+	}
+}
+
+func TestUnknownEnvVars_CommandEnvVars(t *testing.T) {
+	a := &application{
+		DefaultApplication: DefaultApplication{
+			Name:    "App",
+			EnvVars: map[string]EnvVarDefinition{"APP_STYLE": {}},
+			Commands: []*Command{
+				CmdHelp,
+				{
+					UsageLine:  "status",
+					EnvVars:    map[string]EnvVarDefinition{"APP_MODE": {ShortDesc: "Status mode."}},
+					CommandRun: func() CommandRun { return &command{} },
+				},
+			},
+		},
+	}
+	environ := []string{"APP_MODE=1", "APP_MOD=1"}
+	ut.AssertEqual(t, []UnknownEnvVar{{"APP_MOD", "APP_MODE"}}, UnknownEnvVars(a, &EnvVarCheck{}, environ))
+	ut.AssertEqual(t, 0, Run(a, []string{"help"}))
+	ut.AssertEqual(t, true, strings.Contains(a.out.String(), "APP_MODE   Status mode."))
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+)
+
+// runChild runs the executable path with args, connected to os.Stdin and to
+// the application's output, and returns its exit code.
+//
+// env is appended to the current environment. While the child runs, the
+// interrupt signal and the signals in forwardedSignals are forwarded to it.
+// The interrupt signal is not forwarded when the terminal already sent it to
+// the child, see forwardInterrupt, so Ctrl-C is received once. When the child
+// is killed by a signal, the exit code is 128 plus the signal number, like
+// shells do.
+func runChild(a Application, path string, args, env []string) int {
+	return runCmd(a, exec.Command(path, args...), env)
+}
+
+// runCmd runs cmd like runChild. cmd.Stdin defaults to os.Stdin.
+func runCmd(a Application, cmd *exec.Cmd, env []string) int {
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stdout = a.GetOut()
+	cmd.Stderr = a.GetErr()
+	if len(env) != 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, append([]os.Signal{os.Interrupt}, forwardedSignals...)...)
+	defer signal.Stop(sigs)
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(a.GetErr(), "%s %s\n", errorPrefix(a), err)
+		return 1
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case s := <-sigs:
+				if s != os.Interrupt || forwardInterrupt(cmd.Stdin) {
+					// The child may have exited already.
+					_ = cmd.Process.Signal(s)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	err := cmd.Wait()
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if code, ok := signalExitCode(exitErr.ProcessState); ok {
+			return code
+		}
+		if code := exitErr.ExitCode(); code >= 0 {
+			return code
+		}
+	}
+	fmt.Fprintf(a.GetErr(), "%s %s\n", errorPrefix(a), err)
+	return 1
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+//go:build !linux && !darwin && !freebsd
+
+package subcommands
+
+import (
+	"io"
+	"os"
+)
+
+// forwardedSignals are the signals forwarded to child processes, in addition
+// to the interrupt signal. None are forwarded on this platform.
+var forwardedSignals []os.Signal
+
+// forwardInterrupt returns true if the interrupt signal must be forwarded to a
+// child process whose stdin is stdin. It never is on this platform.
+//
+// On Windows, the child receives Ctrl-C from the console directly.
+func forwardInterrupt(stdin io.Reader) bool {
+	return false
+}
+
+// signalExitCode returns the exit code to use for a process killed by a
+// signal. It is not supported on this platform.
+func signalExitCode(s *os.ProcessState) (int, bool) {
+	return 0, false
+}
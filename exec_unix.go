@@ -0,0 +1,46 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+//go:build linux || darwin || freebsd
+
+package subcommands
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// forwardedSignals are the signals forwarded to child processes, in addition
+// to the interrupt signal.
+var forwardedSignals = []os.Signal{syscall.SIGTERM}
+
+// forwardInterrupt returns true if SIGINT must be forwarded to a child
+// process whose stdin is stdin.
+//
+// Ctrl-C makes the terminal send SIGINT to its whole foreground process
+// group, which includes the child. So SIGINT is not forwarded when the
+// application is in the foreground process group of the terminal on stdin,
+// otherwise the child would receive it twice. It is forwarded in all other
+// cases, e.g. when sent by a process supervisor.
+func forwardInterrupt(stdin io.Reader) bool {
+	f, ok := stdin.(*os.File)
+	if !ok {
+		return true
+	}
+	var pgrp int32
+	// #nosec G103
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGPGRP), uintptr(unsafe.Pointer(&pgrp)))
+	return errno != 0 || int(pgrp) != syscall.Getpgrp()
+}
+
+// signalExitCode returns the exit code to use for a process killed by a
+// signal.
+func signalExitCode(s *os.ProcessState) (int, bool) {
+	if ws, ok := s.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal()), true
+	}
+	return 0, false
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+//go:build linux || darwin || freebsd
+
+package subcommands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/maruel/ut"
+)
+
+func TestRunChildSignalDeath(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "killed", "kill -TERM $$\n")
+	a := &application{DefaultApplication: DefaultApplication{Name: "App"}}
+	ut.AssertEqual(t, 128+int(syscall.SIGTERM), runChild(a, filepath.Join(dir, "killed"), nil, nil))
+	ut.AssertEqual(t, "", a.err.String())
+}
+
+func TestRunChildForwardSignal(t *testing.T) {
+	dir := t.TempDir()
+	ready := filepath.Join(dir, "ready")
+	writeScript(t, dir, "trap", `trap 'echo interrupted; exit 7' INT
+: > "`+ready+`"
+while :; do sleep 0.05; done
+`)
+	a := &application{DefaultApplication: DefaultApplication{Name: "App"}}
+	cmd := exec.Command(filepath.Join(dir, "trap"))
+	// stdin is not a terminal, so the signal can't come from Ctrl-C.
+	cmd.Stdin = strings.NewReader("")
+	done := make(chan int)
+	go func() {
+		done <- runCmd(a, cmd, nil)
+	}()
+	for {
+		if _, err := os.Stat(ready); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// The signal is caught while the child runs and forwarded to it.
+	ut.AssertEqual(t, nil, syscall.Kill(os.Getpid(), syscall.SIGINT))
+	ut.AssertEqual(t, 7, <-done)
+	ut.AssertEqual(t, "interrupted\n", a.out.String())
+}
+
+func TestForwardInterrupt(t *testing.T) {
+	ut.AssertEqual(t, true, forwardInterrupt(strings.NewReader("")))
+	f, err := os.Open(os.DevNull)
+	ut.AssertEqual(t, nil, err)
+	defer f.Close()
+	ut.AssertEqual(t, true, forwardInterrupt(f))
+}
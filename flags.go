@@ -0,0 +1,274 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FlagConstraint is a constraint applied on a FlagGroup.
+type FlagConstraint int
+
+const (
+	// MutuallyExclusive means at most one flag of the group can be specified.
+	MutuallyExclusive FlagConstraint = iota
+	// AllOrNone means that either all the flags of the group are specified or
+	// none of them are.
+	AllOrNone
+)
+
+// FlagGroup is a group of flags, referenced by name, on which a constraint is
+// enforced by Run after the flags are parsed.
+type FlagGroup struct {
+	Constraint FlagConstraint
+	Flags      []string
+}
+
+// String returns the constraint as shown in help pages.
+func (g FlagGroup) String() string {
+	switch g.Constraint {
+	case MutuallyExclusive:
+		return joinFlags(g.Flags) + " are mutually exclusive"
+	case AllOrNone:
+		return joinFlags(g.Flags) + " must be specified together"
+	default:
+		return fmt.Sprintf("FlagConstraint(%d) on %s", int(g.Constraint), joinFlags(g.Flags))
+	}
+}
+
+// unknownFlagPrefix is the prefix of the error returned by flag.FlagSet.Parse
+// for an undefined flag.
+const unknownFlagPrefix = "flag provided but not defined: -"
+
+// parseFlags parses args with f, which output is a.GetErr().
+//
+// When a flag is not defined, a short error suggesting the nearest defined
+// flags is printed instead of the flag package's error and full usage. Other
+// errors, including -help, are reported by f as usual.
+func parseFlags(a Application, c *Command, f *flag.FlagSet, args []string) error {
+	// Buffer the output and delay the call to Usage until the error is known.
+	out := f.Output()
+	usage := f.Usage
+	usageCalled := false
+	buf := bytes.Buffer{}
+	f.SetOutput(&buf)
+	f.Usage = func() {
+		usageCalled = true
+	}
+	err := f.Parse(args)
+	f.SetOutput(out)
+	f.Usage = usage
+	if err == nil {
+		return nil
+	}
+	if name, ok := strings.CutPrefix(err.Error(), unknownFlagPrefix); ok {
+		var names []string
+		f.VisitAll(func(fl *flag.Flag) {
+			if _, ok := c.DeprecatedFlags[fl.Name]; !ok {
+				names = append(names, fl.Name)
+			}
+		})
+		var suggestions []string
+		for _, m := range suggestNames(names, name) {
+			suggestions = append(suggestions, names[m.index])
+		}
+		fmt.Fprintf(a.GetErr(), "%s unknown flag -%s\n\n%sRun '%s help %s' for usage.\n", errorPrefix(a), name, didYouMean(suggestions, "-%s"), a.GetName(), c.Name())
+		return err
+	}
+	_, _ = out.Write(buf.Bytes())
+	if usageCalled {
+		if usage != nil {
+			usage()
+		} else {
+			f.PrintDefaults()
+		}
+	}
+	return err
+}
+
+// validateFlags verifies that the flags specified on the command line respect
+// c.RequiredFlags and c.FlagGroups.
+func validateFlags(c *Command, f *flag.FlagSet) error {
+	set := map[string]bool{}
+	f.Visit(func(fl *flag.Flag) {
+		set[fl.Name] = true
+	})
+	for _, name := range c.RequiredFlags {
+		if !set[name] {
+			return fmt.Errorf("flag -%s is required", name)
+		}
+	}
+	for _, g := range c.FlagGroups {
+		var present []string
+		for _, name := range g.Flags {
+			if set[name] {
+				present = append(present, name)
+			}
+		}
+		switch g.Constraint {
+		case MutuallyExclusive:
+			if len(present) > 1 {
+				return fmt.Errorf("flags %s are mutually exclusive", joinFlags(present))
+			}
+		case AllOrNone:
+			if len(present) != 0 && len(present) != len(g.Flags) {
+				return fmt.Errorf("%s must be specified together", joinFlags(g.Flags))
+			}
+		}
+	}
+	return nil
+}
+
+// joinFlags returns "-a, -b and -c".
+func joinFlags(names []string) string {
+	s := make([]string, len(names))
+	for i, n := range names {
+		s[i] = "-" + n
+	}
+	if len(s) < 2 {
+		return strings.Join(s, "")
+	}
+	return strings.Join(s[:len(s)-1], ", ") + " and " + s[len(s)-1]
+}
+
+// warnDeprecatedFlags prints a warning for each deprecated flag specified on
+// the command line.
+func warnDeprecatedFlags(a Application, c *Command, f *flag.FlagSet) {
+	f.Visit(func(fl *flag.Flag) {
+		if repl, ok := c.DeprecatedFlags[fl.Name]; ok {
+			if repl != "" {
+				fmt.Fprintf(a.GetErr(), "%s flag -%s is deprecated, use -%s instead\n", errorPrefix(a), fl.Name, repl)
+			} else {
+				fmt.Fprintf(a.GetErr(), "%s flag -%s is deprecated\n", errorPrefix(a), fl.Name)
+			}
+		}
+	})
+}
+
+// printDefaults is like flag.FlagSet.PrintDefaults but annotates the flags
+// with the metadata in c. Deprecated flags are skipped and advanced flags are
+// skipped unless includeAdvanced is true.
+//
+// Flag names and default values are styled with st, which can be nil.
+//
+// Returns true if an advanced flag was skipped.
+func printDefaults(out io.Writer, st *Style, c *Command, f *flag.FlagSet, includeAdvanced bool) (hasAdvanced bool) {
+	required := map[string]bool{}
+	for _, name := range c.RequiredFlags {
+		required[name] = true
+	}
+	advanced := map[string]bool{}
+	for _, name := range c.AdvancedFlags {
+		advanced[name] = true
+	}
+	f.VisitAll(func(fl *flag.Flag) {
+		if _, ok := c.DeprecatedFlags[fl.Name]; ok {
+			return
+		}
+		if advanced[fl.Name] && !includeAdvanced {
+			hasAdvanced = true
+			return
+		}
+		b := strings.Builder{}
+		b.WriteString("  " + st.Apply(StyleFlag, "-"+fl.Name))
+		name, usage := flag.UnquoteUsage(fl)
+		if t, ok := fl.Value.(valueTyper); ok && name == "value" {
+			name = t.typeName()
+		}
+		if c, ok := fl.Value.(choicer); ok {
+			usage += " (one of: " + strings.Join(c.choices(), ", ") + ")"
+		}
+		if len(name) > 0 {
+			b.WriteString(" ")
+			b.WriteString(name)
+		}
+		// Same formatting as flag.PrintDefaults.
+		if len(fl.Name) <= 1 && name == "" {
+			b.WriteString("\t")
+		} else {
+			b.WriteString("\n    \t")
+		}
+		b.WriteString(strings.ReplaceAll(usage, "\n", "\n    \t"))
+		if !isZeroValue(fl) {
+			def := fl.DefValue
+			if isStringFlag(fl) {
+				def = strconv.Quote(def)
+			}
+			fmt.Fprintf(&b, " (default %s)", st.Apply(StyleDefault, def))
+		}
+		if required[fl.Name] {
+			b.WriteString(" (required)")
+		}
+		fmt.Fprint(out, b.String(), "\n")
+	})
+	for _, g := range c.FlagGroups {
+		fmt.Fprintf(out, "  %s\n", g)
+	}
+	return hasAdvanced
+}
+
+// isStringFlag returns true if the flag holds a string, like the ones created
+// by flag.String. Their default value is quoted, like flag.PrintDefaults does.
+func isStringFlag(fl *flag.Flag) bool {
+	g, ok := fl.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	_, ok = g.Get().(string)
+	return ok
+}
+
+// checkFlagNames panics if c.RequiredFlags, c.FlagGroups, c.AdvancedFlags or
+// c.DeprecatedFlags reference a flag not defined in f, which can be nil. It is
+// a programming error.
+func checkFlagNames(c *Command, f *flag.FlagSet) {
+	check := func(field, name string) {
+		if f == nil || f.Lookup(name) == nil {
+			panic(fmt.Sprintf("subcommands: command %s: %s references undefined flag -%s", c.Name(), field, name))
+		}
+	}
+	for _, name := range c.RequiredFlags {
+		check("RequiredFlags", name)
+	}
+	for _, g := range c.FlagGroups {
+		for _, name := range g.Flags {
+			check("FlagGroups", name)
+		}
+	}
+	for _, name := range c.AdvancedFlags {
+		check("AdvancedFlags", name)
+	}
+	for name, repl := range c.DeprecatedFlags {
+		check("DeprecatedFlags", name)
+		if repl != "" {
+			check("DeprecatedFlags", repl)
+		}
+	}
+}
+
+// isZeroValue determines whether fl.DefValue is the zero value for the flag's
+// type, in which case it is not printed.
+func isZeroValue(fl *flag.Flag) (ok bool) {
+	typ := reflect.TypeOf(fl.Value)
+	var z reflect.Value
+	if typ.Kind() == reflect.Pointer {
+		z = reflect.New(typ.Elem())
+	} else {
+		z = reflect.Zero(typ)
+	}
+	defer func() {
+		// Some flag.Value implementations panic when called on a zero value.
+		if recover() != nil {
+			ok = fl.DefValue == ""
+		}
+	}()
+	return fl.DefValue == z.Interface().(flag.Value).String()
+}
@@ -0,0 +1,261 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/maruel/ut"
+)
+
+func TestPrintDefaults(t *testing.T) {
+	// printDefaults must be a drop-in replacement for flag.PrintDefaults.
+	f := flag.FlagSet{}
+	f.Bool("v", false, "verbose")
+	f.Bool("dry", true, "dry run")
+	f.String("name", "", "the `who` to greet")
+	f.String("style", "Hi", "greeting\nstyle")
+	f.Int("n", 0, "count")
+	f.Duration("d", time.Second, "duration")
+	expected := bytes.Buffer{}
+	f.SetOutput(&expected)
+	f.PrintDefaults()
+	actual := bytes.Buffer{}
+	printDefaults(&actual, nil, &Command{}, &f, false)
+	ut.AssertEqual(t, expected.String(), actual.String())
+}
+
+func TestRunFlagConstraints(t *testing.T) {
+	data := []struct {
+		args []string
+		err  string
+		exit int
+	}{
+		{[]string{"cmd", "-id", "1"}, "", 42},
+		{[]string{"cmd"}, "App: flag -id is required\n\nRun 'App help cmd' for usage.\n", 2},
+		{[]string{"cmd", "-id", "1", "-json", "-text"}, "App: flags -json and -text are mutually exclusive\n\nRun 'App help cmd' for usage.\n", 2},
+		{[]string{"cmd", "-id", "1", "-user", "joe"}, "App: -user and -password must be specified together\n\nRun 'App help cmd' for usage.\n", 2},
+		{[]string{"cmd", "-id", "1", "-user", "joe", "-password", "x", "-text"}, "", 42},
+		{
+			[]string{"help", "cmd"},
+			"usage:  App cmd\n" +
+				"  -id string\n    \tID (required)\n" +
+				"  -json\n    \tJSON output\n" +
+				"  -password string\n    \tPassword\n" +
+				"  -text\n    \tText output\n" +
+				"  -user string\n    \tUser\n" +
+				"  -json and -text are mutually exclusive\n" +
+				"  -user and -password must be specified together\n",
+			0,
+		},
+	}
+	for i, line := range data {
+		line := line
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			a := application{
+				DefaultApplication: DefaultApplication{
+					Name: "App",
+					Commands: []*Command{
+						CmdHelp,
+						{
+							UsageLine:     "cmd",
+							RequiredFlags: []string{"id"},
+							FlagGroups: []FlagGroup{
+								{Constraint: MutuallyExclusive, Flags: []string{"json", "text"}},
+								{Constraint: AllOrNone, Flags: []string{"user", "password"}},
+							},
+							CommandRun: func() CommandRun {
+								c := &command{}
+								c.Flags.String("id", "", "ID")
+								c.Flags.Bool("json", false, "JSON output")
+								c.Flags.Bool("text", false, "Text output")
+								c.Flags.String("user", "", "User")
+								c.Flags.String("password", "", "Password")
+								return c
+							},
+						},
+					},
+				},
+			}
+			ut.AssertEqual(t, line.exit, Run(&a, line.args))
+			ut.AssertEqual(t, line.err, a.err.String())
+		})
+	}
+}
+
+func TestRunAdvancedDeprecatedFlags(t *testing.T) {
+	data := []struct {
+		args []string
+		err  string
+		exit int
+	}{
+		{[]string{"cmd", "-new", "1"}, "", 42},
+		{[]string{"cmd", "-old", "1"}, "App: flag -old is deprecated, use -new instead\n", 42},
+		{[]string{"cmd", "-older"}, "App: flag -older is deprecated\n", 42},
+		{
+			[]string{"help", "cmd"},
+			"usage:  App cmd\n" +
+				"  -new string\n    \tNew\n" +
+				"\n" +
+				"Use \"App help -advanced cmd\" to display all flags.\n",
+			0,
+		},
+		{
+			[]string{"help", "-advanced", "cmd"},
+			"usage:  App cmd\n" +
+				"  -expert\n    \tExpert mode\n" +
+				"  -new string\n    \tNew\n",
+			0,
+		},
+	}
+	for i, line := range data {
+		line := line
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			a := application{
+				DefaultApplication: DefaultApplication{
+					Name: "App",
+					Commands: []*Command{
+						CmdHelp,
+						{
+							UsageLine:       "cmd",
+							AdvancedFlags:   []string{"expert"},
+							DeprecatedFlags: map[string]string{"old": "new", "older": ""},
+							CommandRun: func() CommandRun {
+								c := &command{}
+								c.Flags.Bool("expert", false, "Expert mode")
+								c.Flags.String("new", "", "New")
+								c.Flags.String("old", "", "Old")
+								c.Flags.Bool("older", false, "Older")
+								return c
+							},
+						},
+					},
+				},
+			}
+			ut.AssertEqual(t, line.exit, Run(&a, line.args))
+			ut.AssertEqual(t, line.err, a.err.String())
+			ut.AssertEqual(t, []string{"-expert", "-new"}, Complete(&a, []string{"cmd", "-"}))
+		})
+	}
+}
+
+func TestRunUnknownFlag(t *testing.T) {
+	data := []struct {
+		args []string
+		exit int
+		err  string
+	}{
+		{
+			[]string{"sleep", "-durration", "1s"},
+			2,
+			"App: unknown flag -durration\n\nDid you mean -duration?\n\nRun 'App help sleep' for usage.\n",
+		},
+		{
+			[]string{"sleep", "-d", "1s"},
+			2,
+			"App: unknown flag -d\n\nDid you mean one of: -dream, -duration?\n\nRun 'App help sleep' for usage.\n",
+		},
+		{
+			[]string{"sleep", "-olde"},
+			2,
+			"App: unknown flag -olde\n\nRun 'App help sleep' for usage.\n",
+		},
+		{
+			[]string{"sleep", "-xyz"},
+			2,
+			"App: unknown flag -xyz\n\nRun 'App help sleep' for usage.\n",
+		},
+		{
+			[]string{"sleep", "-duration", "foo"},
+			2,
+			"invalid value \"foo\" for flag -duration: parse error\n" +
+				"usage:  App sleep\n" +
+				"  -dream\n" +
+				"    \tdream while sleeping\n" +
+				"  -duration duration\n" +
+				"    \thow long\n",
+		},
+		{
+			[]string{"sleep", "-help"},
+			2,
+			"usage:  App sleep\n" +
+				"  -dream\n" +
+				"    \tdream while sleeping\n" +
+				"  -duration duration\n" +
+				"    \thow long\n",
+		},
+		{[]string{"custom", "-foo"}, 2, "App: unknown flag -foo\n\nRun 'App help custom' for usage.\n"},
+		{[]string{"custom", "-h"}, 2, "custom usage\n"},
+	}
+	for i, line := range data {
+		line := line
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			a := &application{
+				DefaultApplication: DefaultApplication{
+					Name: "App",
+					Commands: []*Command{
+						{
+							UsageLine:       "sleep",
+							DeprecatedFlags: map[string]string{"old": ""},
+							CommandRun: func() CommandRun {
+								c := &command{}
+								c.Flags.Duration("duration", 0, "how long")
+								c.Flags.Bool("dream", false, "dream while sleeping")
+								c.Flags.Bool("old", false, "old")
+								return c
+							},
+						},
+						{
+							UsageLine: "custom",
+							CommandRun: func() CommandRun {
+								c := &command{}
+								c.Flags.Usage = func() {
+									fmt.Fprintf(c.Flags.Output(), "custom usage\n")
+								}
+								return c
+							},
+						},
+					},
+				},
+			}
+			ut.AssertEqual(t, line.exit, Run(a, line.args))
+			ut.AssertEqual(t, line.err, a.err.String())
+		})
+	}
+}
+
+func TestCheckFlagNames(t *testing.T) {
+	data := []struct {
+		c   Command
+		err string
+	}{
+		{Command{UsageLine: "a", RequiredFlags: []string{"v"}, AdvancedFlags: []string{"n"}}, ""},
+		{Command{UsageLine: "a", RequiredFlags: []string{"x"}}, "subcommands: command a: RequiredFlags references undefined flag -x"},
+		{Command{UsageLine: "a", FlagGroups: []FlagGroup{{Flags: []string{"v", "x"}}}}, "subcommands: command a: FlagGroups references undefined flag -x"},
+		{Command{UsageLine: "a", AdvancedFlags: []string{"x"}}, "subcommands: command a: AdvancedFlags references undefined flag -x"},
+		{Command{UsageLine: "a", DeprecatedFlags: map[string]string{"v": "x"}}, "subcommands: command a: DeprecatedFlags references undefined flag -x"},
+	}
+	f := flag.FlagSet{}
+	f.Bool("v", false, "")
+	f.Int("n", 0, "")
+	for i, line := range data {
+		func() {
+			defer func() {
+				err, _ := recover().(string)
+				ut.AssertEqualIndex(t, i, line.err, err)
+			}()
+			checkFlagNames(&line.c, &f)
+		}()
+	}
+	defer func() {
+		ut.AssertEqual(t, "subcommands: command a: RequiredFlags references undefined flag -v", recover())
+	}()
+	checkFlagNames(&Command{UsageLine: "a", RequiredFlags: []string{"v"}}, nil)
+}
@@ -0,0 +1,572 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Completer is implemented by flag.Value implementations that can suggest
+// values for shell completion.
+type Completer interface {
+	// Complete returns the values starting with prefix.
+	Complete(prefix string) []string
+}
+
+// valueTyper is implemented by the flag.Value implementations in this package
+// to describe the type of the value in help pages.
+type valueTyper interface {
+	typeName() string
+}
+
+// choicer is implemented by flag.Value implementations that only accept a
+// fixed set of values, listed in help pages.
+type choicer interface {
+	choices() []string
+}
+
+// Enum is a flag.Value that only accepts one of the values in Allowed.
+//
+// Usage:
+//
+//	c.color = subcommands.Enum{Allowed: []string{"auto", "always", "never"}, Value: "auto"}
+//	c.Flags.Var(&c.color, "color", "When to use colors")
+type Enum struct {
+	Allowed []string
+	Value   string
+}
+
+// String implements flag.Value.
+func (e *Enum) String() string {
+	return e.Value
+}
+
+// Set implements flag.Value.
+func (e *Enum) Set(s string) error {
+	if !slices.Contains(e.Allowed, s) {
+		return fmt.Errorf("must be one of %s", strings.Join(e.Allowed, ", "))
+	}
+	e.Value = s
+	return nil
+}
+
+// Complete implements Completer.
+func (e *Enum) Complete(prefix string) []string {
+	return filterPrefix(e.Allowed, prefix)
+}
+
+func (e *Enum) typeName() string {
+	return "string"
+}
+
+func (e *Enum) choices() []string {
+	return e.Allowed
+}
+
+// Strings is a flag.Value that accumulates the values of a flag specified
+// multiple times, e.g. "-tag a -tag b". Value is the default value, replaced
+// by the values specified on the command line.
+type Strings struct {
+	Value []string
+	// Choices, if set, are the values suggested for completion. Other values
+	// are accepted.
+	Choices []string
+	set     bool
+}
+
+// String implements flag.Value.
+func (s *Strings) String() string {
+	return strings.Join(s.Value, ", ")
+}
+
+// Set implements flag.Value.
+func (s *Strings) Set(v string) error {
+	if !s.set {
+		s.Value, s.set = nil, true
+	}
+	s.Value = append(s.Value, v)
+	return nil
+}
+
+// Complete implements Completer. It completes with the Choices not already
+// specified.
+func (s *Strings) Complete(prefix string) []string {
+	var out []string
+	for _, v := range filterPrefix(s.Choices, prefix) {
+		if !s.set || !slices.Contains(s.Value, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (s *Strings) typeName() string {
+	return "string"
+}
+
+// Ints is a flag.Value that accumulates the values of an integer flag
+// specified multiple times, e.g. "-id 1 -id 2". Value is the default value,
+// replaced by the values specified on the command line.
+type Ints struct {
+	Value []int
+	// Choices, if set, are the values suggested for completion. Other values
+	// are accepted.
+	Choices []int
+	set     bool
+}
+
+// String implements flag.Value.
+func (i *Ints) String() string {
+	s := make([]string, len(i.Value))
+	for j, v := range i.Value {
+		s[j] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Set implements flag.Value.
+func (i *Ints) Set(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return errors.New("not a valid int")
+	}
+	if !i.set {
+		i.Value, i.set = nil, true
+	}
+	i.Value = append(i.Value, n)
+	return nil
+}
+
+// Complete implements Completer. It completes with the Choices not already
+// specified.
+func (i *Ints) Complete(prefix string) []string {
+	var out []string
+	for _, v := range i.Choices {
+		if s := strconv.Itoa(v); strings.HasPrefix(s, prefix) && (!i.set || !slices.Contains(i.Value, v)) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (i *Ints) typeName() string {
+	return "int"
+}
+
+// CommaList is a flag.Value that accepts a comma separated list of values,
+// e.g. "-os linux,mac". Values are accumulated if the flag is specified
+// multiple times. Value is the default value, replaced by the values
+// specified on the command line.
+type CommaList struct {
+	Value []string
+	// Choices, if set, are the values suggested for completion. Other values
+	// are accepted.
+	Choices []string
+	set     bool
+}
+
+// String implements flag.Value.
+func (c *CommaList) String() string {
+	return strings.Join(c.Value, ",")
+}
+
+// Set implements flag.Value.
+func (c *CommaList) Set(v string) error {
+	if !c.set {
+		c.Value, c.set = nil, true
+	}
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			c.Value = append(c.Value, s)
+		}
+	}
+	return nil
+}
+
+// Complete implements Completer. It completes the last element of the list
+// with the Choices not already in the list.
+func (c *CommaList) Complete(prefix string) []string {
+	i := strings.LastIndexByte(prefix, ',') + 1
+	listed := strings.Split(prefix[:i], ",")
+	var out []string
+	for _, v := range filterPrefix(c.Choices, prefix[i:]) {
+		if !slices.Contains(listed, v) {
+			out = append(out, prefix[:i]+v)
+		}
+	}
+	return out
+}
+
+func (c *CommaList) typeName() string {
+	return "list"
+}
+
+// KeyValues is a flag.Value that accumulates key=value pairs, e.g.
+// "-label os=linux -label arch=amd64". Value is the default value, replaced
+// by the pairs specified on the command line.
+type KeyValues struct {
+	Value map[string]string
+	// Keys, if set, are the keys suggested for completion. Other keys are
+	// accepted.
+	Keys []string
+	set  bool
+}
+
+// String implements flag.Value.
+func (k *KeyValues) String() string {
+	keys := make([]string, 0, len(k.Value))
+	for key := range k.Value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		keys[i] = key + "=" + k.Value[key]
+	}
+	return strings.Join(keys, ",")
+}
+
+// Set implements flag.Value.
+func (k *KeyValues) Set(v string) error {
+	key, value, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return errors.New("expected key=value")
+	}
+	if !k.set {
+		// Do not modify the default map, it may be shared.
+		k.Value, k.set = map[string]string{}, true
+	}
+	k.Value[key] = value
+	return nil
+}
+
+// Complete implements Completer. It completes the key with Keys.
+func (k *KeyValues) Complete(prefix string) []string {
+	if strings.Contains(prefix, "=") {
+		return nil
+	}
+	var out []string
+	for _, key := range filterPrefix(k.Keys, prefix) {
+		out = append(out, key+"=")
+	}
+	return out
+}
+
+func (k *KeyValues) typeName() string {
+	return "key=value"
+}
+
+// Counter is a flag.Value that counts the number of times a boolean flag is
+// specified, e.g. "-v -v -v" is 3. "-v=5" sets the value explicitly.
+type Counter int
+
+// String implements flag.Value.
+func (c *Counter) String() string {
+	return strconv.Itoa(int(*c))
+}
+
+// Set implements flag.Value.
+func (c *Counter) Set(v string) error {
+	switch v {
+	case "true":
+		*c++
+	case "false":
+		*c = 0
+	default:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return errors.New("not a valid int")
+		}
+		*c = Counter(n)
+	}
+	return nil
+}
+
+// IsBoolFlag tells package flag that the flag doesn't require a value.
+func (c *Counter) IsBoolFlag() bool {
+	return true
+}
+
+// ByteSize is a flag.Value that accepts a size in bytes with an optional unit,
+// e.g. "512", "10KB", "1.5MiB". Units ending with "iB" are powers of 1024,
+// others are powers of 1000. Units are case insensitive.
+type ByteSize int64
+
+var byteUnits = []struct {
+	suffix string
+	size   float64
+}{
+	{"kib", 1 << 10},
+	{"mib", 1 << 20},
+	{"gib", 1 << 30},
+	{"tib", 1 << 40},
+	{"kb", 1e3},
+	{"mb", 1e6},
+	{"gb", 1e9},
+	{"tb", 1e12},
+	{"k", 1e3},
+	{"m", 1e6},
+	{"g", 1e9},
+	{"t", 1e12},
+	{"b", 1},
+}
+
+// String implements flag.Value.
+func (b *ByteSize) String() string {
+	v := int64(*b)
+	units := []struct {
+		suffix string
+		shift  uint
+	}{{"TiB", 40}, {"GiB", 30}, {"MiB", 20}, {"KiB", 10}}
+	for _, u := range units {
+		if size := int64(1) << u.shift; v != 0 && v%size == 0 {
+			return strconv.FormatInt(v/size, 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// Set implements flag.Value.
+func (b *ByteSize) Set(v string) error {
+	s := strings.ToLower(strings.TrimSpace(v))
+	mult := 1.
+	for _, u := range byteUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			mult = u.size
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 || f*mult >= math.MaxInt64 {
+		return errors.New("not a valid size")
+	}
+	*b = ByteSize(f * mult)
+	return nil
+}
+
+// Complete implements Completer. It suggests units once a number is typed.
+func (b *ByteSize) Complete(prefix string) []string {
+	if prefix == "" || strings.IndexFunc(prefix, func(r rune) bool { return (r < '0' || r > '9') && r != '.' }) != -1 {
+		return nil
+	}
+	return []string{prefix + "KiB", prefix + "MiB", prefix + "GiB", prefix + "KB", prefix + "MB", prefix + "GB"}
+}
+
+func (b *ByteSize) typeName() string {
+	return "size"
+}
+
+// ExistingFile is a flag.Value that only accepts the path to an existing file.
+type ExistingFile string
+
+// String implements flag.Value.
+func (e *ExistingFile) String() string {
+	return string(*e)
+}
+
+// Set implements flag.Value.
+func (e *ExistingFile) Set(v string) error {
+	fi, err := os.Stat(v)
+	if errors.Is(err, fs.ErrNotExist) {
+		return errors.New("file not found")
+	}
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return errors.New("is a directory")
+	}
+	*e = ExistingFile(v)
+	return nil
+}
+
+// Complete implements Completer.
+func (e *ExistingFile) Complete(prefix string) []string {
+	return completePath(prefix, false)
+}
+
+func (e *ExistingFile) typeName() string {
+	return "file"
+}
+
+// ExistingDir is a flag.Value that only accepts the path to an existing
+// directory.
+type ExistingDir string
+
+// String implements flag.Value.
+func (e *ExistingDir) String() string {
+	return string(*e)
+}
+
+// Set implements flag.Value.
+func (e *ExistingDir) Set(v string) error {
+	fi, err := os.Stat(v)
+	if errors.Is(err, fs.ErrNotExist) {
+		return errors.New("directory not found")
+	}
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return errors.New("not a directory")
+	}
+	*e = ExistingDir(v)
+	return nil
+}
+
+// Complete implements Completer.
+func (e *ExistingDir) Complete(prefix string) []string {
+	return completePath(prefix, true)
+}
+
+func (e *ExistingDir) typeName() string {
+	return "dir"
+}
+
+// TimeRange is a flag.Value that accepts a time range formatted as
+// "<start>..<end>". Each end is either a RFC 3339 timestamp or a date
+// formatted as "2006-01-02", and can be omitted to leave the range open.
+//
+// Completion suggests today's date for each end.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// String implements flag.Value.
+func (t *TimeRange) String() string {
+	if t.Start.IsZero() && t.End.IsZero() {
+		return ""
+	}
+	s := ""
+	if !t.Start.IsZero() {
+		s = t.Start.Format(time.RFC3339)
+	}
+	s += ".."
+	if !t.End.IsZero() {
+		s += t.End.Format(time.RFC3339)
+	}
+	return s
+}
+
+// Set implements flag.Value.
+func (t *TimeRange) Set(v string) error {
+	start, end, ok := strings.Cut(v, "..")
+	if !ok {
+		return errors.New("expected <start>..<end>")
+	}
+	var r TimeRange
+	var err error
+	if r.Start, err = parseTime(start); err != nil {
+		return err
+	}
+	if r.End, err = parseTime(end); err != nil {
+		return err
+	}
+	if !r.Start.IsZero() && !r.End.IsZero() && r.End.Before(r.Start) {
+		return errors.New("end is before start")
+	}
+	*t = r
+	return nil
+}
+
+// Contains returns true if ts is within the range, inclusively.
+func (t *TimeRange) Contains(ts time.Time) bool {
+	return (t.Start.IsZero() || !ts.Before(t.Start)) && (t.End.IsZero() || !ts.After(t.End))
+}
+
+// Complete implements Completer. It suggests today's date, as the start or
+// the end of the range.
+func (t *TimeRange) Complete(prefix string) []string {
+	today := time.Now().Format("2006-01-02")
+	if start, _, ok := strings.Cut(prefix, ".."); ok {
+		return filterPrefix([]string{start + ".." + today}, prefix)
+	}
+	return filterPrefix([]string{today + "..", ".." + today}, prefix)
+}
+
+func (t *TimeRange) typeName() string {
+	return "range"
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if ts, err := time.Parse(layout, s); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// Regexp is a flag.Value that accepts a regular expression.
+type Regexp struct {
+	*regexp.Regexp
+}
+
+// String implements flag.Value.
+func (r *Regexp) String() string {
+	if r.Regexp == nil {
+		return ""
+	}
+	return r.Regexp.String()
+}
+
+// Set implements flag.Value.
+func (r *Regexp) Set(v string) error {
+	re, err := regexp.Compile(v)
+	if err != nil {
+		return err
+	}
+	r.Regexp = re
+	return nil
+}
+
+func (r *Regexp) typeName() string {
+	return "regexp"
+}
+
+// filterPrefix returns the items starting with prefix.
+func filterPrefix(items []string, prefix string) []string {
+	var out []string
+	for _, i := range items {
+		if strings.HasPrefix(i, prefix) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// completePath returns the paths starting with prefix. Directories always
+// have a trailing separator so the completion can continue inside.
+func completePath(prefix string, dirOnly bool) []string {
+	matches, _ := filepath.Glob(prefix + "*")
+	var out []string
+	for _, m := range matches {
+		fi, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+		if fi.IsDir() {
+			out = append(out, m+string(filepath.Separator))
+		} else if !dirOnly {
+			out = append(out, m)
+		}
+	}
+	return out
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/maruel/ut"
+)
+
+func TestFlagValues(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	ut.AssertEqual(t, nil, os.WriteFile(file, nil, 0o600))
+
+	color := Enum{Allowed: []string{"auto", "always", "never"}, Value: "auto"}
+	tags := Strings{Value: []string{"default"}}
+	var ids Ints
+	oses := CommaList{Value: []string{"default"}}
+	defaultLabels := map[string]string{"c": "3"}
+	labels := KeyValues{Value: defaultLabels}
+	var verbose Counter
+	var size ByteSize
+	var in ExistingFile
+	var out ExistingDir
+	var during TimeRange
+	var filter Regexp
+	f := flag.NewFlagSet("test", flag.ContinueOnError)
+	f.Var(&color, "color", "When to use colors")
+	f.Var(&tags, "tag", "Tags")
+	f.Var(&ids, "id", "IDs")
+	f.Var(&oses, "os", "OSes")
+	f.Var(&labels, "label", "Labels")
+	f.Var(&verbose, "v", "Verbosity")
+	f.Var(&size, "size", "Size")
+	f.Var(&in, "in", "Input")
+	f.Var(&out, "out", "Output")
+	f.Var(&during, "during", "Period")
+	f.Var(&filter, "filter", "Filter")
+	args := []string{
+		"-color", "never",
+		"-tag", "a", "-tag", "b",
+		"-id", "1", "-id", "2",
+		"-os", "linux,mac", "-os", "win",
+		"-label", "a=1", "-label", "b=",
+		"-v", "-v", "-v",
+		"-size", "1.5MiB",
+		"-in", file,
+		"-out", dir,
+		"-during", "2020-01-02..",
+		"-filter", "^a+$",
+	}
+	ut.AssertEqual(t, nil, f.Parse(args))
+	ut.AssertEqual(t, "never", color.Value)
+	ut.AssertEqual(t, []string{"a", "b"}, tags.Value)
+	ut.AssertEqual(t, []int{1, 2}, ids.Value)
+	ut.AssertEqual(t, []string{"linux", "mac", "win"}, oses.Value)
+	ut.AssertEqual(t, map[string]string{"a": "1", "b": ""}, labels.Value)
+	ut.AssertEqual(t, map[string]string{"c": "3"}, defaultLabels)
+	ut.AssertEqual(t, "a=1,b=", labels.String())
+	ut.AssertEqual(t, Counter(3), verbose)
+	ut.AssertEqual(t, ByteSize(1536*1024), size)
+	ut.AssertEqual(t, "1536KiB", size.String())
+	ut.AssertEqual(t, ExistingFile(file), in)
+	ut.AssertEqual(t, ExistingDir(dir), out)
+	ut.AssertEqual(t, "2020-01-02T00:00:00Z..", during.String())
+	ut.AssertEqual(t, true, during.Contains(time.Now()))
+	ut.AssertEqual(t, false, during.Contains(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)))
+	ut.AssertEqual(t, true, filter.MatchString("aaa"))
+}
+
+func TestFlagValues_Error(t *testing.T) {
+	dir := t.TempDir()
+	data := []struct {
+		v     flag.Value
+		value string
+	}{
+		{&Enum{Allowed: []string{"a"}}, "b"},
+		{new(Ints), "a"},
+		{new(KeyValues), "a"},
+		{new(KeyValues), "=a"},
+		{new(Counter), "a"},
+		{new(ByteSize), "10XB"},
+		{new(ByteSize), "-1"},
+		{new(ByteSize), "8388608TiB"},
+		{new(ByteSize), "9223372036854775807"},
+		{new(ExistingFile), filepath.Join(dir, "missing")},
+		{new(ExistingFile), dir},
+		{new(ExistingDir), filepath.Join(dir, "missing")},
+		{new(TimeRange), "2020-01-01"},
+		{new(TimeRange), "2020-01-02..2020-01-01"},
+		{new(TimeRange), "yesterday.."},
+		{new(Regexp), "("},
+	}
+	for i, line := range data {
+		ut.AssertEqualIndex(t, i, true, line.v.Set(line.value) != nil)
+	}
+}
+
+func TestFlagValues_ErrorMessage(t *testing.T) {
+	r := Regexp{}
+	ut.AssertEqual(t, "error parsing regexp: missing closing ): `(`", r.Set("(").Error())
+	e := ExistingFile("")
+	ut.AssertEqual(t, "file not found", e.Set(filepath.Join(t.TempDir(), "missing")).Error())
+	if runtime.GOOS == "windows" || os.Getuid() == 0 {
+		t.Skip("root ignores permissions")
+	}
+	dir := filepath.Join(t.TempDir(), "locked")
+	ut.AssertEqual(t, nil, os.Mkdir(dir, 0o700))
+	ut.AssertEqual(t, nil, os.WriteFile(filepath.Join(dir, "file"), nil, 0o600))
+	ut.AssertEqual(t, nil, os.Chmod(dir, 0))
+	t.Cleanup(func() { _ = os.Chmod(dir, 0o700) })
+	ut.AssertEqual(t, true, errors.Is(e.Set(filepath.Join(dir, "file")), fs.ErrPermission))
+}
+
+func TestByteSize(t *testing.T) {
+	data := []struct {
+		in       string
+		expected ByteSize
+	}{
+		{"0", 0},
+		{"512", 512},
+		{"512b", 512},
+		{"10k", 10000},
+		{"10KB", 10000},
+		{"10KiB", 10240},
+		{"2 MiB", 2 << 20},
+		{"1GB", 1e9},
+		{"1TiB", 1 << 40},
+	}
+	for i, line := range data {
+		var b ByteSize
+		ut.AssertEqualIndex(t, i, nil, b.Set(line.in))
+		ut.AssertEqualIndex(t, i, line.expected, b)
+	}
+}
+
+func TestFlagValues_Help(t *testing.T) {
+	f := flag.NewFlagSet("test", flag.ContinueOnError)
+	f.Var(&Enum{Allowed: []string{"auto", "never"}, Value: "auto"}, "color", "When to use colors")
+	f.Var(new(Counter), "v", "Verbosity")
+	f.Var(new(ByteSize), "size", "Maximum `bytes`")
+	f.Var(new(KeyValues), "label", "Labels")
+	buf := bytes.Buffer{}
+	printDefaults(&buf, nil, &Command{}, f, false)
+	expected := "  -color string\n" +
+		"    \tWhen to use colors (one of: auto, never) (default auto)\n" +
+		"  -label key=value\n" +
+		"    \tLabels\n" +
+		"  -size bytes\n" +
+		"    \tMaximum bytes\n" +
+		"  -v\tVerbosity\n"
+	ut.AssertEqual(t, expected, buf.String())
+}
+
+func TestFlagValues_Complete(t *testing.T) {
+	a := &DefaultApplication{
+		Commands: []*Command{
+			{
+				UsageLine: "paint",
+				CommandRun: func() CommandRun {
+					c := &command{}
+					c.Flags.Var(&Enum{Allowed: []string{"blue", "green", "red"}}, "color", "")
+					c.Flags.Var(new(ByteSize), "size", "")
+					c.Flags.Var(new(Counter), "v", "")
+					c.Flags.Var(&CommaList{Choices: []string{"linux", "mac", "win"}}, "os", "")
+					c.Flags.Var(&KeyValues{Keys: []string{"arch", "os"}}, "label", "")
+					c.Flags.Var(new(TimeRange), "during", "")
+					c.Flags.Var(&Strings{Choices: []string{"api", "db", "web"}}, "tag", "")
+					c.Flags.Var(&Ints{Value: []int{1}, Choices: []int{1, 2, 10}}, "id", "")
+					return c
+				},
+			},
+		},
+	}
+	ut.AssertEqual(t, []string{"green"}, Complete(a, []string{"paint", "-color", "g"}))
+	ut.AssertEqual(t, []string{"-color=blue"}, Complete(a, []string{"paint", "-color=b"}))
+	ut.AssertEqual(t, []string{"1KiB", "1MiB", "1GiB", "1KB", "1MB", "1GB"}, Complete(a, []string{"paint", "-v", "--size", "1"}))
+	ut.AssertEqual(t, []string(nil), Complete(a, []string{"paint", "-v", ""}))
+	ut.AssertEqual(t, []string{"linux,mac", "linux,win"}, Complete(a, []string{"paint", "-os", "linux,"}))
+	ut.AssertEqual(t, []string{"arch=", "os="}, Complete(a, []string{"paint", "-label", ""}))
+	ut.AssertEqual(t, []string(nil), Complete(a, []string{"paint", "-label", "os="}))
+	ut.AssertEqual(t, []string{"api", "web"}, Complete(a, []string{"paint", "-tag", "db", "-tag", ""}))
+	ut.AssertEqual(t, []string{"-tag=api", "-tag=db"}, Complete(a, []string{"paint", "-tag=web", "-tag="}))
+	// The default value isn't excluded.
+	ut.AssertEqual(t, []string{"1", "10"}, Complete(a, []string{"paint", "-id", "1"}))
+	ut.AssertEqual(t, []string{"2", "10"}, Complete(a, []string{"paint", "-id", "1", "-id", ""}))
+	today := time.Now().Format("2006-01-02")
+	ut.AssertEqual(t, []string{today + "..", ".." + today}, Complete(a, []string{"paint", "-during", ""}))
+	ut.AssertEqual(t, []string{"2020-01-01.." + today}, Complete(a, []string{"paint", "-during", "2020-01-01.."}))
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/texttheater/golang-levenshtein/levenshtein"
+)
+
+// MatchPolicy controls how Run finds the command to run from the name typed
+// on the command line.
+type MatchPolicy int
+
+const (
+	// MatchFuzzy runs the command found by FindNearestCommand: exact match,
+	// unique prefix, case insensitive unique prefix or the closest name by
+	// Levenshtein distance. This is the default.
+	MatchFuzzy MatchPolicy = iota
+	// MatchExact only runs a command typed in full.
+	MatchExact
+	// MatchPrefix runs a command typed in full or a unique prefix of it.
+	MatchPrefix
+	// MatchSuggest only runs a command typed in full. Otherwise, the command
+	// found by FindNearestCommand is suggested but not run.
+	MatchSuggest
+)
+
+// String returns the name of the policy.
+func (m MatchPolicy) String() string {
+	switch m {
+	case MatchFuzzy:
+		return "fuzzy"
+	case MatchExact:
+		return "exact"
+	case MatchPrefix:
+		return "prefix"
+	case MatchSuggest:
+		return "suggest"
+	default:
+		return fmt.Sprintf("MatchPolicy(%d)", int(m))
+	}
+}
+
+// MatchPolicyApplication is implemented by an Application that specifies how
+// the command to run is found. Applications that do not implement it use
+// MatchFuzzy.
+//
+// Commands with Command.ExactMatch set are only run when typed in full,
+// independently of the policy.
+type MatchPolicyApplication interface {
+	Application
+	GetMatchPolicy() MatchPolicy
+}
+
+// getMatchPolicy returns the MatchPolicy of a.
+func getMatchPolicy(a Application) MatchPolicy {
+	if m, ok := AppAs[MatchPolicyApplication](a); ok {
+		return m.GetMatchPolicy()
+	}
+	return MatchFuzzy
+}
+
+// matchCommand returns the command to run for name according to the
+// application's MatchPolicy, or the command to suggest instead if it
+// shouldn't be run.
+func matchCommand(a Application, name string) (run, suggestion *Command) {
+	if c := FindCommand(a, name); c != nil && !c.isSection {
+		return c, nil
+	}
+	cmds := runnableCommands(a)
+	var found *Command
+	switch getMatchPolicy(a) {
+	case MatchExact:
+		return nil, nil
+	case MatchPrefix:
+		if i := uniqueMatch(commandNames(cmds), func(n string) bool { return strings.HasPrefix(n, name) }); i >= 0 {
+			found = cmds[i]
+		}
+	case MatchSuggest:
+		return nil, FindNearestCommand(a, name)
+	default:
+		found = FindNearestCommand(a, name)
+	}
+	if found != nil && found.ExactMatch {
+		return nil, found
+	}
+	return found, nil
+}
+
+// Suggestion is a command suggested by SuggestCommands.
+type Suggestion struct {
+	Command *Command
+	// Distance is the Levenshtein distance between the command's name and the
+	// name typed, as used by FindNearestCommand where a substitution costs 2.
+	Distance int
+}
+
+// maxSuggestionDistance is the maximum Levenshtein distance of a suggestion
+// that is not a prefix match.
+const maxSuggestionDistance = 3
+
+// SuggestCommands returns the commands the user may have wanted to type
+// instead of name, most likely first, using the application's Matcher.
+//
+// With DefaultMatcher, it uses the same passes as FindNearestCommand: commands
+// starting with name rank first, then commands starting with name case
+// insensitively, then the commands within a small Levenshtein distance. Within
+// a pass, candidates are sorted by distance.
+func SuggestCommands(a Application, name string) []Suggestion {
+	return getMatcher(a).Suggest(runnableCommands(a), name)
+}
+
+// nameMatch is a name suggested by suggestNames.
+type nameMatch struct {
+	index    int
+	distance int
+	pass     int
+}
+
+// suggestNames returns the names similar to name, most likely first. See
+// SuggestCommands for the ranking. name itself is never suggested.
+func suggestNames(names []string, name string) []nameMatch {
+	var out []nameMatch
+	lowName := strings.ToLower(name)
+	for i, n := range names {
+		m := nameMatch{index: i, distance: levenshtein.DistanceForStrings([]rune(n), []rune(name), levenshtein.DefaultOptions)}
+		switch {
+		case n == name:
+			continue
+		case strings.HasPrefix(n, name):
+		case strings.HasPrefix(strings.ToLower(n), lowName):
+			m.pass = 1
+		case m.distance <= maxSuggestionDistance:
+			m.pass = 2
+		default:
+			continue
+		}
+		out = append(out, m)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].pass != out[j].pass {
+			return out[i].pass < out[j].pass
+		}
+		return out[i].distance < out[j].distance
+	})
+	return out
+}
+
+// didYouMean returns a hint listing the suggestions, formatted with format,
+// or an empty string if there is none.
+func didYouMean(suggestions []string, format string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	quoted := make([]string, 0, maxSuggestions)
+	for _, s := range suggestions[:min(len(suggestions), maxSuggestions)] {
+		quoted = append(quoted, fmt.Sprintf(format, s))
+	}
+	if len(quoted) == 1 {
+		return "Did you mean " + quoted[0] + "?\n\n"
+	}
+	return "Did you mean one of: " + strings.Join(quoted, ", ") + "?\n\n"
+}
+
+// maxSuggestions is the maximum number of suggestions printed.
+const maxSuggestions = 5
+
+// printUnknownCommand prints that name is not a known command. suggestion is
+// the command to suggest, if any. Otherwise the candidates returned by
+// SuggestCommands are suggested.
+func printUnknownCommand(a Application, name string, suggestion *Command) {
+	var names []string
+	if suggestion != nil {
+		names = []string{suggestion.Name()}
+	} else {
+		for _, s := range SuggestCommands(a, name) {
+			names = append(names, s.Command.Name())
+		}
+	}
+	fmt.Fprintf(a.GetErr(), "%s unknown command %#q\n\n%sRun '%s help' for usage.\n", errorPrefix(a), name, didYouMean(names, "%#q"), a.GetName())
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func TestMatchPolicy(t *testing.T) {
+	data := []struct {
+		policy MatchPolicy
+		arg    string
+		exit   int
+		err    string
+	}{
+		{MatchFuzzy, "status", 42, ""},
+		{MatchFuzzy, "stat", 42, ""},
+		{MatchFuzzy, "STAT", 42, ""},
+		{MatchFuzzy, "statsu", 42, ""},
+		{MatchFuzzy, "destroy", 42, ""},
+		{MatchFuzzy, "dest", 2, "App: unknown command `dest`\n\nDid you mean `destroy`?\n\nRun 'App help' for usage.\n"},
+		{MatchExact, "status", 42, ""},
+		{MatchExact, "stat", 2, "App: unknown command `stat`\n\nDid you mean `status`?\n\nRun 'App help' for usage.\n"},
+		{MatchPrefix, "stat", 42, ""},
+		{MatchPrefix, "STAT", 2, "App: unknown command `STAT`\n\nDid you mean `status`?\n\nRun 'App help' for usage.\n"},
+		{MatchPrefix, "statsu", 2, "App: unknown command `statsu`\n\nDid you mean `status`?\n\nRun 'App help' for usage.\n"},
+		{MatchPrefix, "dest", 2, "App: unknown command `dest`\n\nDid you mean `destroy`?\n\nRun 'App help' for usage.\n"},
+		{MatchSuggest, "status", 42, ""},
+		{MatchSuggest, "statsu", 2, "App: unknown command `statsu`\n\nDid you mean `status`?\n\nRun 'App help' for usage.\n"},
+		{MatchSuggest, "xyz", 2, "App: unknown command `xyz`\n\nRun 'App help' for usage.\n"},
+	}
+	for i, line := range data {
+		line := line
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			a := &application{
+				DefaultApplication: DefaultApplication{
+					Name:        "App",
+					MatchPolicy: line.policy,
+					Commands: []*Command{
+						{UsageLine: "status", CommandRun: func() CommandRun { return &command{} }},
+						{UsageLine: "destroy", ExactMatch: true, CommandRun: func() CommandRun { return &command{} }},
+					},
+				},
+			}
+			ut.AssertEqual(t, line.exit, Run(a, []string{line.arg}))
+			ut.AssertEqual(t, line.err, a.err.String())
+		})
+	}
+}
+
+func TestMatchPolicy_String(t *testing.T) {
+	ut.AssertEqual(t, "fuzzy", MatchFuzzy.String())
+	ut.AssertEqual(t, "suggest", MatchSuggest.String())
+	ut.AssertEqual(t, "MatchPolicy(42)", MatchPolicy(42).String())
+}
+
+func TestSuggestCommands(t *testing.T) {
+	a := &DefaultApplication{
+		Commands: []*Command{
+			{UsageLine: "push"},
+			Section("Misc"),
+			{UsageLine: "pull"},
+			{UsageLine: "Publish"},
+			{UsageLine: "status"},
+			{UsageLine: "pu"},
+		},
+	}
+	data := []struct {
+		name     string
+		expected []string
+	}{
+		{"pu", []string{"push", "pull", "Publish"}},
+		{"pus", []string{"push", "pu", "pull"}},
+		{"PUB", []string{"Publish"}},
+		{"xyz", nil},
+		{"status", nil},
+	}
+	for i, line := range data {
+		var names []string
+		for _, s := range SuggestCommands(a, line.name) {
+			names = append(names, s.Command.Name())
+		}
+		ut.AssertEqualIndex(t, i, line.expected, names)
+	}
+	ut.AssertEqual(t, []Suggestion{{a.Commands[0], 1}, {a.Commands[5], 1}, {a.Commands[2], 3}}, SuggestCommands(a, "pus"))
+}
+
+func TestRun_DidYouMean(t *testing.T) {
+	a := &application{
+		DefaultApplication: DefaultApplication{
+			Name: "App",
+			Commands: []*Command{
+				CmdHelp,
+				{UsageLine: "push"},
+				{UsageLine: "pull"},
+				{UsageLine: "pulse"},
+			},
+		},
+	}
+	expected := "App: unknown command `pu`\n\nDid you mean one of: `push`, `pull`, `pulse`?\n\nRun 'App help' for usage.\n"
+	ut.AssertEqual(t, 2, Run(a, []string{"pu"}))
+	ut.AssertEqual(t, expected, a.err.String())
+	a.err.Reset()
+	ut.AssertEqual(t, 2, Run(a, []string{"help", "pu"}))
+	ut.AssertEqual(t, expected, a.err.String())
+}
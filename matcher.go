@@ -0,0 +1,278 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Matcher finds the command the user wanted to type but failed to type
+// correctly.
+//
+// cmds never contains sections. Commands typed in full or matching one of
+// their Command.Aliases are found before the Matcher is used.
+type Matcher interface {
+	// Find returns the command to run for name, or nil if there is none or if
+	// it is ambiguous.
+	Find(cmds []*Command, name string) *Command
+	// Suggest returns the commands similar to name, most likely first.
+	Suggest(cmds []*Command, name string) []Suggestion
+}
+
+// MatcherApplication is implemented by an Application that specifies its
+// Matcher. Applications that do not implement it, or return nil, use
+// DefaultMatcher.
+type MatcherApplication interface {
+	Application
+	GetMatcher() Matcher
+}
+
+// getMatcher returns the Matcher of a.
+func getMatcher(a Application) Matcher {
+	if m, ok := AppAs[MatcherApplication](a); ok {
+		if matcher := m.GetMatcher(); matcher != nil {
+			return matcher
+		}
+	}
+	return DefaultMatcher{}
+}
+
+// findAlias returns the command in cmds having name as an alias.
+func findAlias(cmds []*Command, name string) *Command {
+	for _, c := range cmds {
+		for _, alias := range c.Aliases {
+			if alias == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// DefaultMatcher is the Matcher used by default. It finds the command by
+// exact name, then unique prefix, then case insensitive unique prefix, then
+// the closest name by Levenshtein distance, as long as it is close enough and
+// not ambiguous.
+//
+// Aliases are only matched exactly.
+type DefaultMatcher struct{}
+
+// Find implements Matcher.
+func (DefaultMatcher) Find(cmds []*Command, name string) *Command {
+	names := commandNames(cmds)
+	if !slices.Contains(names, name) {
+		if c := findAlias(cmds, name); c != nil {
+			return c
+		}
+	}
+	if i := findNearest(names, name); i >= 0 {
+		return cmds[i]
+	}
+	return nil
+}
+
+// Suggest implements Matcher.
+func (DefaultMatcher) Suggest(cmds []*Command, name string) []Suggestion {
+	matches := suggestNames(commandNames(cmds), name)
+	out := make([]Suggestion, len(matches))
+	for i, m := range matches {
+		out[i] = Suggestion{cmds[m.index], m.distance}
+	}
+	return out
+}
+
+func commandNames(cmds []*Command) []string {
+	names := make([]string, len(cmds))
+	for i, c := range cmds {
+		names[i] = c.Name()
+	}
+	return names
+}
+
+// TypoMatcher is a Matcher tuned for typing mistakes.
+//
+// Names are compared case insensitively with a Damerau-Levenshtein distance
+// where swapping two adjacent letters costs 1, hitting a neighbor key on a
+// QWERTY keyboard costs 0.5 and repeating a letter costs 0.5. Aliases are
+// matched like the command's name.
+//
+// A unique prefix of a name or alias is also matched.
+type TypoMatcher struct {
+	// MaxDistance is the maximum distance of a match. When 0, it is 1 for
+	// names of up to 4 characters, 2 up to 8 characters and 3 otherwise.
+	MaxDistance float64
+	// MinGap is the minimum difference between the distance of the best match
+	// and the next one for the best match to be run. When 0, it is 1.
+	MinGap float64
+}
+
+// typoCandidate is a command and its distance to the name typed.
+type typoCandidate struct {
+	cmd      *Command
+	distance float64
+	prefix   bool
+}
+
+// candidates returns the commands within the maximum distance of name, or
+// having a name or an alias starting with name, closest first. Each command
+// is listed once.
+func (t TypoMatcher) candidates(cmds []*Command, name string) []typoCandidate {
+	maxD := t.MaxDistance
+	if maxD == 0 {
+		switch n := utf8.RuneCountInString(name); {
+		case n <= 4:
+			maxD = 1
+		case n <= 8:
+			maxD = 2
+		default:
+			maxD = 3
+		}
+	}
+	lowName := strings.ToLower(name)
+	var out []typoCandidate
+	for _, c := range cmds {
+		best := typoCandidate{cmd: c, distance: math.Inf(1)}
+		for _, n := range append([]string{c.Name()}, c.Aliases...) {
+			if strings.HasPrefix(strings.ToLower(n), lowName) {
+				best.prefix = true
+			}
+			if d := TypoDistance(n, name); d < best.distance {
+				best.distance = d
+			}
+		}
+		if best.prefix || best.distance <= maxD {
+			out = append(out, best)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].prefix != out[j].prefix {
+			return out[i].prefix
+		}
+		return out[i].distance < out[j].distance
+	})
+	return out
+}
+
+// Find implements Matcher.
+func (t TypoMatcher) Find(cmds []*Command, name string) *Command {
+	if name == "" {
+		return nil
+	}
+	c := t.candidates(cmds, name)
+	if len(c) == 0 {
+		return nil
+	}
+	if c[0].prefix {
+		if len(c) == 1 || !c[1].prefix {
+			return c[0].cmd
+		}
+		// Ambiguous prefix, an exact case insensitive match wins.
+		if c[0].distance == 0 && c[1].distance != 0 {
+			return c[0].cmd
+		}
+		return nil
+	}
+	gap := t.MinGap
+	if gap == 0 {
+		gap = 1
+	}
+	if len(c) > 1 && c[1].distance-c[0].distance < gap {
+		return nil
+	}
+	return c[0].cmd
+}
+
+// Suggest implements Matcher. Distance is rounded up.
+func (t TypoMatcher) Suggest(cmds []*Command, name string) []Suggestion {
+	var out []Suggestion
+	for _, c := range t.candidates(cmds, name) {
+		if c.distance == 0 {
+			continue
+		}
+		out = append(out, Suggestion{c.cmd, int(math.Ceil(c.distance))})
+	}
+	return out
+}
+
+// qwertyRows is the layout of the letters on a QWERTY keyboard, with the
+// horizontal offset of each row.
+var qwertyRows = []struct {
+	keys   string
+	offset float64
+}{
+	{"qwertyuiop", 0},
+	{"asdfghjkl", 0.25},
+	{"zxcvbnm", 0.75},
+}
+
+// qwertyAdjacent returns true if a and b are neighbor keys.
+func qwertyAdjacent(a, b rune) bool {
+	ra, xa, oka := qwertyPos(a)
+	rb, xb, okb := qwertyPos(b)
+	if !oka || !okb || a == b {
+		return false
+	}
+	switch ra - rb {
+	case 0:
+		return math.Abs(xa-xb) == 1
+	case 1, -1:
+		return math.Abs(xa-xb) <= 0.75
+	}
+	return false
+}
+
+func qwertyPos(r rune) (int, float64, bool) {
+	for i, row := range qwertyRows {
+		if j := strings.IndexRune(row.keys, r); j >= 0 {
+			return i, float64(j) + row.offset, true
+		}
+	}
+	return 0, 0, false
+}
+
+// TypoDistance returns the distance used by TypoMatcher between the name of a
+// command and the name typed.
+func TypoDistance(name, typed string) float64 {
+	a := []rune(strings.ToLower(name))
+	b := []rune(strings.ToLower(typed))
+	// Optimal string alignment distance.
+	d := make([][]float64, len(a)+1)
+	for i := range d {
+		d[i] = make([]float64, len(b)+1)
+		d[i][0] = float64(i)
+	}
+	for j := range d[0] {
+		d[0][j] = float64(j)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			sub := 1.
+			if a[i-1] == b[j-1] {
+				sub = 0
+			} else if qwertyAdjacent(a[i-1], b[j-1]) {
+				sub = 0.5
+			}
+			// Inserting or deleting a repeated letter is a cheap mistake.
+			ins := 1.
+			if j > 1 && b[j-1] == b[j-2] {
+				ins = 0.5
+			}
+			del := 1.
+			if i > 1 && a[i-1] == a[i-2] {
+				del = 0.5
+			}
+			v := min(d[i-1][j]+del, d[i][j-1]+ins, d[i-1][j-1]+sub)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				v = min(v, d[i-2][j-2]+1)
+			}
+			d[i][j] = v
+		}
+	}
+	return d[len(a)][len(b)]
+}
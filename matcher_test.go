@@ -0,0 +1,298 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+// typoCommands is a set of commands typical of a version control tool.
+func typoCommands() []*Command {
+	var out []*Command
+	for _, n := range []string{"status", "commit", "checkout", "push", "pull", "branch", "merge", "rebase", "log", "diff", "remove", "list", "clone", "config", "cherry-pick"} {
+		c := &Command{UsageLine: n}
+		switch n {
+		case "remove":
+			c.Aliases = []string{"rm"}
+		case "list":
+			c.Aliases = []string{"ls"}
+		case "checkout":
+			c.Aliases = []string{"co"}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func TestTypoMatcher_Corpus(t *testing.T) {
+	// Common typing mistakes and the command that was meant.
+	data := []struct {
+		typed    string
+		expected string
+	}{
+		// Transpositions.
+		{"stauts", "status"},
+		{"sttaus", "status"},
+		{"commti", "commit"},
+		{"chekcout", "checkout"},
+		{"psuh", "push"},
+		{"brnach", "branch"},
+		{"mrege", "merge"},
+		{"rebsae", "rebase"},
+		{"lgo", "log"},
+		{"dfif", "diff"},
+		{"lsit", "list"},
+		{"clnoe", "clone"},
+		{"cherry-pikc", "cherry-pick"},
+		{"puhs", "push"},
+		// Missing or doubled letters.
+		{"comit", "commit"},
+		{"commmit", "commit"},
+		{"stattus", "status"},
+		{"chckout", "checkout"},
+		{"brach", "branch"},
+		// Neighbor keys.
+		{"pusj", "push"},
+		{"ststus", "status"},
+		{"cimmit", "commit"},
+		{"merhe", "merge"},
+		{"rebasw", "rebase"},
+		{"diff", "diff"},
+		// Case.
+		{"STATUS", "status"},
+		{"Stauts", "status"},
+		// Prefixes.
+		{"stat", "status"},
+		{"chec", "checkout"},
+		{"che", ""},
+		{"cherry", "cherry-pick"},
+		{"con", "config"},
+		{"pul", "pull"},
+		// Aliases.
+		{"rm", "remove"},
+		{"ls", "list"},
+		{"co", "checkout"},
+		{"rn", "remove"},
+		// Too far or ambiguous.
+		{"xyz", ""},
+		{"pu", ""},
+		{"c", ""},
+		{"pusl", ""},
+		{"deploy", ""},
+		{"", ""},
+	}
+	cmds := typoCommands()
+	m := TypoMatcher{}
+	for i, line := range data {
+		c := m.Find(cmds, line.typed)
+		name := ""
+		if c != nil {
+			name = c.Name()
+		}
+		ut.AssertEqualIndex(t, i, line.expected, name)
+	}
+}
+
+func TestTypoMatcher_Suggest(t *testing.T) {
+	cmds := typoCommands()
+	data := []struct {
+		typed    string
+		expected []string
+	}{
+		{"pusl", []string{"push", "pull"}},
+		{"pu", []string{"pull", "push"}},
+		{"stauts", []string{"status"}},
+		{"status", nil},
+		{"xyz", nil},
+	}
+	for i, line := range data {
+		var names []string
+		for _, s := range (TypoMatcher{}).Suggest(cmds, line.typed) {
+			names = append(names, s.Command.Name())
+		}
+		ut.AssertEqualIndex(t, i, line.expected, names)
+	}
+}
+
+func TestTypoDistance(t *testing.T) {
+	data := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"status", "status", 0},
+		{"status", "STATUS", 0},
+		{"status", "stauts", 1},
+		{"push", "pusj", 0.5},
+		{"push", "pusk", 1},
+		{"commit", "comit", 0.5},
+		{"commit", "commmit", 0.5},
+		{"status", "stattus", 0.5},
+		{"log", "", 3},
+		{"", "log", 3},
+	}
+	for i, line := range data {
+		ut.AssertEqualIndex(t, i, line.expected, TypoDistance(line.a, line.b))
+	}
+}
+
+func TestQwertyAdjacent(t *testing.T) {
+	ut.AssertEqual(t, true, qwertyAdjacent('j', 'h'))
+	ut.AssertEqual(t, true, qwertyAdjacent('s', 'w'))
+	ut.AssertEqual(t, true, qwertyAdjacent('s', 'x'))
+	ut.AssertEqual(t, true, qwertyAdjacent('b', 'h'))
+	ut.AssertEqual(t, false, qwertyAdjacent('a', 'd'))
+	ut.AssertEqual(t, false, qwertyAdjacent('q', 'z'))
+	ut.AssertEqual(t, false, qwertyAdjacent('a', 'a'))
+	ut.AssertEqual(t, false, qwertyAdjacent('-', 'a'))
+}
+
+func TestDefaultMatcher(t *testing.T) {
+	cmds := []*Command{
+		{UsageLine: "remove", Aliases: []string{"rm", "list"}},
+		{UsageLine: "list"},
+		{UsageLine: "rmdir"},
+	}
+	data := []struct {
+		typed    string
+		expected string
+	}{
+		{"rm", "remove"},
+		{"list", "list"},
+		{"rem", "remove"},
+		{"rmd", "rmdir"},
+		{"lsit", "list"},
+		{"xyz", ""},
+	}
+	for i, line := range data {
+		c := DefaultMatcher{}.Find(cmds, line.typed)
+		name := ""
+		if c != nil {
+			name = c.Name()
+		}
+		ut.AssertEqualIndex(t, i, line.expected, name)
+	}
+}
+
+// reverseMatcher matches commands typed backward.
+type reverseMatcher struct{}
+
+func (reverseMatcher) Find(cmds []*Command, name string) *Command {
+	for _, c := range cmds {
+		if reverse(c.Name()) == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (r reverseMatcher) Suggest(cmds []*Command, name string) []Suggestion {
+	if c := r.Find(cmds, reverse(name)); c != nil {
+		return []Suggestion{{c, 0}}
+	}
+	return nil
+}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func TestRunMatcher(t *testing.T) {
+	data := []struct {
+		matcher Matcher
+		arg     string
+		exit    int
+		err     string
+	}{
+		{nil, "stats", 42, ""},
+		{nil, "rm", 42, ""},
+		{nil, "ststsu", 2, "App: unknown command `ststsu`\n\nRun 'App help' for usage.\n"},
+		{TypoMatcher{}, "ststsu", 42, ""},
+		{TypoMatcher{}, "rn", 42, ""},
+		{TypoMatcher{}, "stats", 42, ""},
+		{reverseMatcher{}, "sutats", 42, ""},
+		{reverseMatcher{}, "stats", 2, "App: unknown command `stats`\n\nRun 'App help' for usage.\n"},
+		{reverseMatcher{}, "status", 42, ""},
+		{reverseMatcher{}, "rm", 42, ""},
+		{reverseMatcher{}, "status" + "x", 2, "App: unknown command `statusx`\n\nRun 'App help' for usage.\n"},
+	}
+	for i, line := range data {
+		a := &application{
+			DefaultApplication: DefaultApplication{
+				Name:    "App",
+				Matcher: line.matcher,
+				Commands: []*Command{
+					{UsageLine: "status", CommandRun: func() CommandRun { return &command{} }},
+					{UsageLine: "remove", Aliases: []string{"rm"}, CommandRun: func() CommandRun { return &command{} }},
+				},
+			},
+		}
+		ut.AssertEqualIndex(t, i, line.exit, Run(a, []string{line.arg}))
+		ut.AssertEqualIndex(t, i, line.err, a.err.String())
+	}
+}
+
+func TestCmdHelp_Matcher(t *testing.T) {
+	data := []struct {
+		matcher Matcher
+		arg     string
+		exit    int
+		err     string
+	}{
+		{nil, "ststsu", 2, "App: unknown command `ststsu`\n\nRun 'App help' for usage.\n"},
+		{TypoMatcher{}, "ststsu", 0, "usage:  App status\n"},
+		{TypoMatcher{}, "stauts", 0, "usage:  App status\n"},
+		{TypoMatcher{}, "rn", 0, "usage:  App remove\n"},
+	}
+	for i, line := range data {
+		a := &application{
+			DefaultApplication: DefaultApplication{
+				Name:    "App",
+				Matcher: line.matcher,
+				Commands: []*Command{
+					CmdHelp,
+					{UsageLine: "status", CommandRun: func() CommandRun { return &command{} }},
+					{UsageLine: "remove", Aliases: []string{"rm"}, CommandRun: func() CommandRun { return &command{} }},
+				},
+				HelpTopics: []*HelpTopic{{Name: "config", ShortDesc: "configuration"}},
+			},
+		}
+		ut.AssertEqualIndex(t, i, line.exit, Run(a, []string{"help", line.arg}))
+		if !strings.HasPrefix(a.err.String(), line.err) {
+			t.Fatalf("%d: expected prefix %q, got %q", i, line.err, a.err.String())
+		}
+	}
+
+	a := &application{
+		DefaultApplication: DefaultApplication{
+			Name:       "App",
+			Matcher:    TypoMatcher{},
+			Commands:   []*Command{CmdHelp},
+			HelpTopics: []*HelpTopic{{Name: "config", ShortDesc: "configuration"}},
+		},
+	}
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "cofnig"}))
+	ut.AssertEqual(t, "configuration\n", a.out.String())
+}
+
+func TestCommandHelpAliases(t *testing.T) {
+	a := &application{
+		DefaultApplication: DefaultApplication{
+			Name: "App",
+			Commands: []*Command{
+				CmdHelp,
+				{UsageLine: "remove <path>", ShortDesc: "removes", Aliases: []string{"rm", "del"}, CommandRun: func() CommandRun { return &command{} }},
+			},
+		},
+	}
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "rm"}))
+	ut.AssertEqual(t, "usage:  App remove <path>\naliases:  rm, del\n  <path>\n", a.err.String())
+}
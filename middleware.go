@@ -0,0 +1,63 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+// Invocation describes a command about to be executed by Run.
+type Invocation struct {
+	// App is the application passed to Run.
+	App Application
+	// Command is the command resolved from the command line.
+	Command *Command
+	// CommandRun is the initialized command; its flags are already parsed.
+	CommandRun CommandRun
+	// Args are the positional arguments, after flags parsing.
+	Args []string
+	// Env is the resolved environment variables.
+	Env Env
+}
+
+// Handler executes an Invocation and returns the exit code.
+type Handler func(inv *Invocation) int
+
+// Middleware wraps the execution of commands. It can act before and after
+// calling next, or return an exit code without calling next to short-circuit
+// the command.
+//
+// Middlewares are run in order: first the ones returned by
+// MiddlewareApplication.GetMiddlewares, then the ones in
+// Command.Middlewares. When a command runs a nested application, the nested
+// application's middlewares run inside the ones of the parent application.
+type Middleware func(next Handler) Handler
+
+// MiddlewareApplication is optionally implemented by an Application to wrap
+// the execution of all its commands.
+//
+// It is found through ApplicationWrapper.Unwrap so it works with mocks.
+type MiddlewareApplication interface {
+	GetMiddlewares() []Middleware
+}
+
+// GetMiddlewares implements MiddlewareApplication.
+func (a *DefaultApplication) GetMiddlewares() []Middleware {
+	return a.Middlewares
+}
+
+// runInvocation runs inv through the middlewares of the application and the
+// command.
+func runInvocation(inv *Invocation) int {
+	h := func(inv *Invocation) int {
+		return inv.CommandRun.Run(inv.App, inv.Args, inv.Env)
+	}
+	for i := len(inv.Command.Middlewares) - 1; i >= 0; i-- {
+		h = inv.Command.Middlewares[i](h)
+	}
+	if m, ok := AppAs[MiddlewareApplication](inv.App); ok {
+		mws := m.GetMiddlewares()
+		for i := len(mws) - 1; i >= 0; i-- {
+			h = mws[i](h)
+		}
+	}
+	return h(inv)
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+type nestedRun struct {
+	CommandRunBase
+	inner Application
+}
+
+func (n *nestedRun) Run(a Application, args []string, env Env) int {
+	return Run(n.inner, args)
+}
+
+func TestMiddlewares(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(inv *Invocation) int {
+				calls = append(calls, name+">"+inv.Command.Name()+":"+strings.Join(inv.Args, ","))
+				ret := next(inv)
+				calls = append(calls, fmt.Sprintf("%s<%d", name, ret))
+				return ret
+			}
+		}
+	}
+	inner := &application{
+		DefaultApplication: DefaultApplication{
+			Name:        "inner",
+			Middlewares: []Middleware{trace("inner")},
+			Commands: []*Command{
+				{
+					UsageLine:   "leaf [<arg>]",
+					Middlewares: []Middleware{trace("leaf")},
+					CommandRun:  func() CommandRun { return &command{} },
+				},
+			},
+		},
+	}
+	outer := &application{
+		DefaultApplication: DefaultApplication{
+			Name:        "outer",
+			Middlewares: []Middleware{trace("outer1"), trace("outer2")},
+			Commands: []*Command{
+				{
+					UsageLine:   "nested <args>",
+					FreeForm:    true,
+					Middlewares: []Middleware{trace("nested")},
+					CommandRun:  func() CommandRun { return &nestedRun{inner: inner} },
+				},
+			},
+		},
+	}
+	ut.AssertEqual(t, 42, Run(outer, []string{"nested", "leaf", "x"}))
+	expected := []string{
+		"outer1>nested:leaf,x",
+		"outer2>nested:leaf,x",
+		"nested>nested:leaf,x",
+		"inner>leaf:x",
+		"leaf>leaf:x",
+		"leaf<42",
+		"inner<42",
+		"nested<42",
+		"outer2<42",
+		"outer1<42",
+	}
+	ut.AssertEqual(t, expected, calls)
+}
+
+func TestMiddlewares_ShortCircuit(t *testing.T) {
+	deny := func(next Handler) Handler {
+		return func(inv *Invocation) int {
+			if inv.Env["SUBCOMMANDS_TEST_TOKEN"].Value == "" {
+				fmt.Fprintf(inv.App.GetErr(), "%s: not authorized\n", inv.App.GetName())
+				return 3
+			}
+			return next(inv)
+		}
+	}
+	a := &application{
+		DefaultApplication: DefaultApplication{
+			Name:        "App",
+			Middlewares: []Middleware{deny},
+			EnvVars:     map[string]EnvVarDefinition{"SUBCOMMANDS_TEST_TOKEN": {}},
+			Commands: []*Command{
+				{UsageLine: "foo", CommandRun: func() CommandRun { return &command{} }},
+			},
+		},
+	}
+	ut.AssertEqual(t, 3, Run(a, []string{"foo"}))
+	ut.AssertEqual(t, "App: not authorized\n", a.err.String())
+}
@@ -0,0 +1,401 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PluginSection is the name of the section listing the plugins added by
+// AddPlugins.
+const PluginSection = "External commands."
+
+// AddPlugins returns cmds followed by the plugins found for the application
+// appName, under their own section.
+//
+// A plugin is an executable named "<appName>-<command>", like git does. The
+// directories in dirs are searched first, then the directories in PATH. When
+// multiple executables have the same name, the first one found is used.
+// Plugins named like a command in cmds are ignored.
+//
+// Plugins receive their arguments unchanged, inherit the environment and
+// write to the application's GetOut and GetErr. Their exit code is returned
+// by Run.
+//
+// Plugins can describe themselves to appear in help pages, docs and
+// completion like built-in commands. See PluginDescribeArg. Plugins are only
+// asked to describe themselves when their description is needed, e.g. to
+// print a command's help or to run them, and concurrently. The list of
+// commands only shows the cached descriptions.
+func AddPlugins(cmds []*Command, appName string, dirs ...string) []*Command {
+	return addPlugins(cmds, appName, newPluginDescriber(), dirs)
+}
+
+// addPlugins implements AddPlugins, describing the plugins with d.
+func addPlugins(cmds []*Command, appName string, d *pluginDescriber, dirs []string) []*Command {
+	seen := map[string]bool{}
+	for _, c := range cmds {
+		if !c.isSection {
+			seen[c.Name()] = true
+		}
+	}
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+	var plugins []*Command
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		for _, p := range findPlugins(appName, dir) {
+			if !seen[p.name] {
+				seen[p.name] = true
+				plugins = append(plugins, lazyPluginCommand(p.name, p.path, d))
+			}
+		}
+	}
+	if len(plugins) == 0 {
+		return cmds
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name() < plugins[j].Name() })
+	out := make([]*Command, 0, len(cmds)+len(plugins)+1)
+	out = append(out, cmds...)
+	out = append(out, Section(PluginSection))
+	return append(out, plugins...)
+}
+
+// plugin is an executable found by findPlugins.
+type plugin struct {
+	name string
+	path string
+}
+
+// findPlugins returns the plugins for appName in dir.
+func findPlugins(appName, dir string) []plugin {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	prefix := appName + "-"
+	var out []plugin
+	for _, e := range entries {
+		n := e.Name()
+		if !strings.HasPrefix(n, prefix) || e.IsDir() {
+			continue
+		}
+		p := filepath.Join(dir, n)
+		fi, err := os.Stat(p)
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		name := n[len(prefix):]
+		if runtime.GOOS == "windows" {
+			ext := strings.ToLower(filepath.Ext(name))
+			if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+				continue
+			}
+			name = name[:len(name)-len(ext)]
+		} else if fi.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		if name != "" {
+			out = append(out, plugin{name, p})
+		}
+	}
+	return out
+}
+
+// PluginDescribeArg is the argument used to ask a plugin to describe itself.
+//
+// A plugin supporting it prints a PluginDescription as JSON to stdout and
+// exits with 0. Otherwise, the plugin is listed with a generic description.
+// The description is cached in the user's cache directory until the plugin's
+// executable is modified.
+const PluginDescribeArg = "--subcommands-describe"
+
+// PluginDescription is the metadata a plugin reports when run with
+// PluginDescribeArg.
+//
+// Example:
+//
+//	{
+//	  "usage_line": "deploy [-force] <env>",
+//	  "short_desc": "deploys the app",
+//	  "args": [{"name": "env", "choices": ["prod", "staging"]}],
+//	  "flags": [{"name": "force", "usage": "skips checks", "bool": true}]
+//	}
+type PluginDescription struct {
+	// UsageLine must start with the command name. It defaults to
+	// "<command> [args...]".
+	UsageLine string `json:"usage_line"`
+	ShortDesc string `json:"short_desc"`
+	LongDesc  string `json:"long_desc"`
+	Advanced  bool   `json:"advanced"`
+	// Args are the positional arguments. When empty, they are derived from
+	// UsageLine.
+	Args  []PluginArg  `json:"args"`
+	Flags []PluginFlag `json:"flags"`
+}
+
+// PluginArg describes a positional argument of a plugin. See Arg.
+type PluginArg struct {
+	Name     string   `json:"name"`
+	Desc     string   `json:"desc"`
+	Optional bool     `json:"optional"`
+	Variadic bool     `json:"variadic"`
+	Choices  []string `json:"choices"`
+}
+
+// PluginFlag describes a flag of a plugin.
+//
+// When a plugin declares flags, they are parsed before running the plugin and
+// passed to it as "-name=value", or "-name" for a bool flag set to true,
+// followed by the remaining arguments.
+type PluginFlag struct {
+	Name    string `json:"name"`
+	Usage   string `json:"usage"`
+	Default string `json:"default"`
+	// Bool is true if the flag doesn't take a value.
+	Bool bool `json:"bool"`
+	// Choices, if set, lists the values used for completion.
+	Choices []string `json:"choices"`
+}
+
+// pluginDescriber asks plugins to describe themselves.
+type pluginDescriber struct {
+	// cacheDir is the directory where the descriptions are cached. They are not
+	// cached when empty.
+	cacheDir string
+	// timeout is the maximum time a plugin is given to describe itself.
+	timeout time.Duration
+}
+
+// newPluginDescriber returns a pluginDescriber caching the descriptions in the
+// user's cache directory.
+func newPluginDescriber() *pluginDescriber {
+	d := &pluginDescriber{timeout: 5 * time.Second}
+	if dir, err := os.UserCacheDir(); err == nil {
+		d.cacheDir = filepath.Join(dir, "subcommands", "plugins")
+	}
+	return d
+}
+
+// pluginCacheEntry is the content of a cache file of a plugin's description.
+type pluginCacheEntry struct {
+	Path    string             `json:"path"`
+	ModTime int64              `json:"mod_time"`
+	Size    int64              `json:"size"`
+	Desc    *PluginDescription `json:"desc"`
+}
+
+// cacheFile returns the file caching the description of the plugin at path,
+// or "" if caching is disabled.
+func (d *pluginDescriber) cacheFile(path string) string {
+	if d.cacheDir == "" {
+		return ""
+	}
+	h := sha256.Sum256([]byte(path))
+	return filepath.Join(d.cacheDir, hex.EncodeToString(h[:16])+".json")
+}
+
+// cached returns the cached description of the plugin at path, which can be
+// nil if it doesn't describe itself, and true if it is up to date.
+func (d *pluginDescriber) cached(path string) (*PluginDescription, bool) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	return d.cachedFor(path, fi)
+}
+
+// cachedFor is cached for the plugin at path with the file info fi.
+func (d *pluginDescriber) cachedFor(path string, fi os.FileInfo) (*PluginDescription, bool) {
+	cacheFile := d.cacheFile(path)
+	if cacheFile == "" {
+		return nil, false
+	}
+	b, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return nil, false
+	}
+	e := pluginCacheEntry{}
+	if json.Unmarshal(b, &e) != nil || e.Path != path || e.ModTime != fi.ModTime().UnixNano() || e.Size != fi.Size() {
+		return nil, false
+	}
+	return e.Desc, true
+}
+
+// describe returns the description of the plugin at path, or nil if it
+// doesn't describe itself. The result is cached, including when there is no
+// description, keyed by the executable's modification time and size.
+func (d *pluginDescriber) describe(path string) *PluginDescription {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if desc, ok := d.cachedFor(path, fi); ok {
+		return desc
+	}
+	desc := d.run(path)
+	if cacheFile := d.cacheFile(path); cacheFile != "" {
+		e := pluginCacheEntry{Path: path, ModTime: fi.ModTime().UnixNano(), Size: fi.Size(), Desc: desc}
+		if b, err := json.Marshal(&e); err == nil {
+			// The cache is best effort.
+			if os.MkdirAll(d.cacheDir, 0o700) == nil {
+				_ = os.WriteFile(cacheFile, b, 0o600)
+			}
+		}
+	}
+	return desc
+}
+
+// run runs the plugin at path with PluginDescribeArg and returns the
+// description it printed, or nil.
+func (d *pluginDescriber) run(path string) *PluginDescription {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+	out := bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, path, PluginDescribeArg)
+	cmd.Stdout = &out
+	// Only the plugin is killed on timeout. Do not wait for its children still
+	// holding stdout.
+	cmd.WaitDelay = 100 * time.Millisecond
+	if err := cmd.Run(); err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+		return nil
+	}
+	desc := &PluginDescription{}
+	if json.Unmarshal(out.Bytes(), desc) != nil {
+		return nil
+	}
+	return desc
+}
+
+// lazyPluginCommand returns the Command running the plugin at path, described
+// by d when the description is first needed.
+func lazyPluginCommand(name, path string, d *pluginDescriber) *Command {
+	c := pluginCommand(name, path, nil)
+	c.loader = &commandLoader{
+		load: func() *Command {
+			return pluginCommand(name, path, d.describe(path))
+		},
+		cached: func() *Command {
+			if desc, ok := d.cached(path); ok {
+				return pluginCommand(name, path, desc)
+			}
+			return nil
+		},
+	}
+	return c
+}
+
+// pluginCommand returns the Command running the plugin at path. desc can be
+// nil.
+func pluginCommand(name, path string, desc *PluginDescription) *Command {
+	c := &Command{
+		UsageLine: name + " [args...]",
+		ShortDesc: "runs " + path,
+		FreeForm:  true,
+	}
+	var flags []PluginFlag
+	if desc != nil {
+		if strings.HasPrefix(desc.UsageLine, name+" ") || desc.UsageLine == name {
+			c.UsageLine = desc.UsageLine
+			c.FreeForm = false
+		}
+		if desc.ShortDesc != "" {
+			c.ShortDesc = desc.ShortDesc
+		}
+		c.LongDesc = desc.LongDesc
+		c.Advanced = desc.Advanced
+		for _, a := range desc.Args {
+			c.Args = append(c.Args, Arg{Name: a.Name, Desc: a.Desc, Optional: a.Optional, Variadic: a.Variadic, Choices: a.Choices})
+		}
+		flags = desc.Flags
+	}
+	c.CommandRun = func() CommandRun {
+		r := &pluginRun{path: path}
+		if len(flags) != 0 {
+			r.flags = &flag.FlagSet{}
+			for _, f := range flags {
+				v := &pluginFlagValue{set: &r.set, flag: f, value: f.Default}
+				r.flags.Var(v, f.Name, f.Usage)
+			}
+		}
+		return r
+	}
+	return c
+}
+
+// pluginRun runs a plugin. Unless the plugin declared flags, it has no flags
+// so the arguments are passed unchanged.
+type pluginRun struct {
+	path  string
+	flags *flag.FlagSet
+	// set are the flags set on the command line, in order, as passed to the
+	// plugin.
+	set []string
+}
+
+func (p *pluginRun) Run(a Application, args []string, env Env) int {
+	return runChild(a, p.path, append(p.set, args...), nil)
+}
+
+func (p *pluginRun) GetFlags() *flag.FlagSet {
+	return p.flags
+}
+
+// pluginFlagValue is the flag.Value of a flag declared by a plugin or a
+// script command. When set is not nil, it records the flags set to forward
+// them to the plugin.
+type pluginFlagValue struct {
+	set   *[]string
+	flag  PluginFlag
+	value string
+}
+
+func (v *pluginFlagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *pluginFlagValue) Set(s string) error {
+	if v.flag.Bool {
+		if _, err := strconv.ParseBool(s); err != nil {
+			return err
+		}
+	}
+	v.value = s
+	switch {
+	case v.set == nil:
+	case v.flag.Bool && s == "true":
+		*v.set = append(*v.set, "-"+v.flag.Name)
+	default:
+		*v.set = append(*v.set, "-"+v.flag.Name+"="+s)
+	}
+	return nil
+}
+
+func (v *pluginFlagValue) IsBoolFlag() bool {
+	return v.flag.Bool
+}
+
+// Complete implements Completer.
+func (v *pluginFlagValue) Complete(prefix string) []string {
+	return filterPrefix(v.flag.Choices, prefix)
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maruel/ut"
+)
+
+// writeScript writes an executable shell script in dir.
+func writeScript(t *testing.T, dir, name, content string) {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testDescriber returns a pluginDescriber caching the descriptions in a
+// temporary directory.
+func testDescriber(t *testing.T) *pluginDescriber {
+	return &pluginDescriber{cacheDir: t.TempDir(), timeout: 5 * time.Second}
+}
+
+// deployPlugin is a plugin describing itself, appending a line to count
+// every time it is described.
+func deployPlugin(count string) string {
+	return `if [ "$1" = "` + PluginDescribeArg + `" ]; then
+  echo x >> "` + count + `"
+  echo '{"usage_line": "deploy [-force] [-mode <mode>] <env>", "short_desc": "deploys the app", "long_desc": "Deploys the app to an environment.",'
+  echo ' "args": [{"name": "env", "desc": "Environment.", "choices": ["prod", "staging"]}],'
+  echo ' "flags": [{"name": "force", "usage": "skips checks", "bool": true}, {"name": "mode", "usage": "deployment mode", "default": "fast", "choices": ["fast", "safe"]}]}'
+  exit 0
+fi
+echo "$*"
+`
+}
+
+func TestAddPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	t.Setenv("PATH", dir2)
+	writeScript(t, dir1, "App-echo", "echo \"out: $*\"\necho \"err: $#\" >&2\nexit 3\n")
+	writeScript(t, dir1, "App-status", "exit 1\n")
+	writeScript(t, dir2, "App-echo", "exit 4\n")
+	writeScript(t, dir2, "App-other", "exit 5\n")
+	writeScript(t, dir2, "Other-app", "exit 6\n")
+	if err := os.WriteFile(filepath.Join(dir2, "App-data"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir2, "App-dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmds := addPlugins([]*Command{{UsageLine: "status", CommandRun: func() CommandRun { return &command{} }}}, "App", testDescriber(t), []string{dir1})
+	var names []string
+	for _, c := range cmds {
+		names = append(names, c.Name())
+	}
+	ut.AssertEqual(t, []string{"status", "", "echo", "other"}, names)
+	ut.AssertEqual(t, true, cmds[1].IsSection())
+	ut.AssertEqual(t, "runs "+filepath.Join(dir1, "App-echo"), cmds[2].ShortDesc)
+
+	a := &application{DefaultApplication: DefaultApplication{Name: "App", Commands: cmds}}
+	ut.AssertEqual(t, 3, Run(a, []string{"echo", "-flag", "a b", "--", "c"}))
+	ut.AssertEqual(t, "out: -flag a b -- c\n", a.out.String())
+	ut.AssertEqual(t, "err: 4\n", a.err.String())
+
+	a = &application{DefaultApplication: DefaultApplication{Name: "App", Commands: cmds}}
+	ut.AssertEqual(t, 5, Run(a, []string{"other"}))
+	ut.AssertEqual(t, 42, Run(a, []string{"status"}))
+}
+
+func TestAddPluginsNone(t *testing.T) {
+	t.Setenv("PATH", "")
+	cmds := []*Command{{UsageLine: "status"}}
+	ut.AssertEqual(t, cmds, AddPlugins(cmds, "App", t.TempDir(), filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestRunChildError(t *testing.T) {
+	a := &application{DefaultApplication: DefaultApplication{Name: "App"}}
+	ut.AssertEqual(t, 1, runChild(a, filepath.Join(t.TempDir(), "missing"), nil, nil))
+	ut.AssertEqual(t, true, a.err.Len() != 0)
+}
+
+func TestPluginDescribe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	t.Setenv("PATH", "")
+	dir := t.TempDir()
+	count := filepath.Join(dir, "count")
+	writeScript(t, dir, "App-deploy", deployPlugin(count))
+	writeScript(t, dir, "App-plain", "echo \"$*\"\n")
+	writeScript(t, dir, "App-bad", "echo '{'\n")
+
+	d := testDescriber(t)
+	cmds := addPlugins([]*Command{CmdHelp}, "App", d, []string{dir})
+	ut.AssertEqual(t, 5, len(cmds))
+	// The plugins are described when needed, not to list the commands.
+	a := &application{DefaultApplication: DefaultApplication{Name: "App", Commands: cmds}}
+	ut.AssertEqual(t, 0, Run(a, []string{"help"}))
+	ut.AssertEqual(t, true, strings.Contains(a.out.String(), "runs "+filepath.Join(dir, "App-deploy")))
+	_, err := os.Stat(count)
+	ut.AssertEqual(t, true, os.IsNotExist(err))
+	loaded := loadCommands(cmds...)
+	ut.AssertEqual(t, "runs "+filepath.Join(dir, "App-bad"), loaded[2].ShortDesc)
+	ut.AssertEqual(t, "deploys the app", loaded[3].ShortDesc)
+	ut.AssertEqual(t, "plain [args...]", loaded[4].UsageLine)
+	// The original command is not modified.
+	ut.AssertEqual(t, "deploy [args...]", cmds[3].UsageLine)
+	loadCommands(cmds...)
+	b, err := os.ReadFile(count)
+	ut.AssertEqual(t, nil, err)
+	ut.AssertEqual(t, "x\n", string(b))
+
+	// The description is cached, and used to list the commands.
+	a = &application{DefaultApplication: DefaultApplication{Name: "App", Commands: addPlugins([]*Command{CmdHelp}, "App", d, []string{dir})}}
+	ut.AssertEqual(t, 0, Run(a, []string{"help"}))
+	ut.AssertEqual(t, true, strings.Contains(a.out.String(), "deploys the app"))
+	loadCommands(a.Commands...)
+	b, _ = os.ReadFile(count)
+	ut.AssertEqual(t, "x\n", string(b))
+
+	// A modified plugin is described again.
+	later := time.Now().Add(time.Hour)
+	ut.AssertEqual(t, nil, os.Chtimes(filepath.Join(dir, "App-deploy"), later, later))
+	loadCommands(addPlugins(nil, "App", d, []string{dir})...)
+	b, _ = os.ReadFile(count)
+	ut.AssertEqual(t, "x\nx\n", string(b))
+
+	a = &application{DefaultApplication: DefaultApplication{Name: "App", Commands: cmds}}
+	ut.AssertEqual(t, 0, Run(a, []string{"deploy", "-force", "-mode", "safe", "prod"}))
+	ut.AssertEqual(t, "-force -mode=safe prod\n", a.out.String())
+
+	a = &application{DefaultApplication: DefaultApplication{Name: "App", Commands: cmds}}
+	ut.AssertEqual(t, 2, Run(a, []string{"deploy", "dev"}))
+	ut.AssertEqual(t, "", a.out.String())
+
+	a = &application{DefaultApplication: DefaultApplication{Name: "App", Commands: cmds}}
+	ut.AssertEqual(t, 0, Run(a, []string{"plain", "-x", "--", "y"}))
+	ut.AssertEqual(t, "-x -- y\n", a.out.String())
+
+	a = &application{DefaultApplication: DefaultApplication{Name: "App", Commands: cmds}}
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "deploy"}))
+	expected := "Deploys the app to an environment.\n\n" +
+		"usage:  App deploy [-force] [-mode <mode>] <env>\n" +
+		"  <env>\n" +
+		"    \tEnvironment.\n" +
+		"  -force\n" +
+		"    \tskips checks\n" +
+		"  -mode value\n" +
+		"    \tdeployment mode (default fast)\n"
+	ut.AssertEqual(t, expected, a.err.String())
+
+	ut.AssertEqual(t, []string{"staging"}, Complete(a, []string{"deploy", "-force", "st"}))
+	ut.AssertEqual(t, []string{"safe"}, Complete(a, []string{"deploy", "-mode", "s"}))
+	ut.AssertEqual(t, []string{"-mode"}, Complete(a, []string{"deploy", "-m"}))
+}
+
+func TestPluginDescribeTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip(err)
+	}
+	t.Setenv("PATH", "")
+	dir := t.TempDir()
+	// The child processes keep stdout open after the plugin is killed.
+	writeScript(t, dir, "App-slow", sleep+" 5 &\n"+sleep+" 5\n")
+	writeScript(t, dir, "App-daemon", sleep+" 5 &\necho '{\"short_desc\": \"starts a daemon\"}'\n")
+
+	pd := &pluginDescriber{cacheDir: t.TempDir(), timeout: 100 * time.Millisecond}
+	start := time.Now()
+	cmds := loadCommands(addPlugins(nil, "App", pd, []string{dir})...)
+	if d := time.Since(start); d > 2*time.Second {
+		t.Fatalf("describing took %s", d)
+	}
+	ut.AssertEqual(t, "starts a daemon", cmds[1].ShortDesc)
+	ut.AssertEqual(t, "runs "+filepath.Join(dir, "App-slow"), cmds[2].ShortDesc)
+}
+
+func TestPluginConcurrentLoad(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	t.Setenv("PATH", "")
+	dir := t.TempDir()
+	writeScript(t, dir, "App-deploy", deployPlugin(filepath.Join(dir, "count")))
+	cmds := addPlugins([]*Command{CmdHelp}, "App", testDescriber(t), []string{dir})
+	a := &application{DefaultApplication: DefaultApplication{Name: "App", Commands: cmds}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			FindCommand(a, "deploy")
+			Usage(io.Discard, a, false)
+		}
+	}()
+	loaded := loadCommands(cmds...)
+	<-done
+	ut.AssertEqual(t, cmds[2], FindCommand(a, "deploy"))
+	ut.AssertEqual(t, "deploys the app", loaded[2].ShortDesc)
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// HelpRenderer renders the help pages.
+//
+// Implement it to fully control the layout of the help pages, or use a
+// TemplateRenderer to only override the templates.
+type HelpRenderer interface {
+	// RenderUsage renders the application's usage, as printed by Usage.
+	RenderUsage(w io.Writer, d *UsageData) error
+	// RenderCommandHelp renders the help of a command, as printed by
+	// "<app> help <command>" or "<app> <command> -help".
+	RenderCommandHelp(w io.Writer, d *CommandHelpData) error
+}
+
+// HelpRendererApplication is implemented by an Application that renders its
+// help pages with a custom HelpRenderer. GetHelpRenderer can return nil to use
+// the default renderer.
+type HelpRendererApplication interface {
+	Application
+	GetHelpRenderer() HelpRenderer
+}
+
+// UsageData is the data used to render the application's usage.
+type UsageData struct {
+	Title string
+	Name  string
+	// Commands are the commands to list, including the sections created by
+	// Section. Advanced commands are only included when requested.
+	Commands []*Command
+	// HelpTopics are the help topics to list.
+	HelpTopics []*HelpTopic
+	// EnvVars are the environment variables to list, sorted by name.
+	EnvVars []UsageEnvVar
+	// ShowAdvancedTip is true when advanced commands or environment variables
+	// were skipped.
+	ShowAdvancedTip bool
+	// WidestCmd is the display width of the longest command name and CmdIndent
+	// the column where the commands' descriptions start.
+	WidestCmd int
+	CmdIndent int
+	// WidestTopic is the display width of the longest help topic name and
+	// TopicIndent the column where their descriptions start.
+	WidestTopic int
+	TopicIndent int
+	// WidestEnvVar is the display width of the longest environment variable
+	// name and EnvVarIndent the column where their descriptions start.
+	WidestEnvVar int
+	EnvVarIndent int
+	// Style is the style to use, nil if the output shouldn't be styled.
+	Style *Style
+}
+
+// UsageEnvVar is an environment variable as listed by Usage.
+type UsageEnvVar struct {
+	Name      string
+	ShortDesc string
+	Default   string
+}
+
+// Desc returns the description of the environment variable including its
+// default value.
+func (e UsageEnvVar) Desc() string {
+	if e.Default == "" {
+		return e.ShortDesc
+	}
+	return fmt.Sprintf("%s (Default: %q)", e.ShortDesc, e.Default)
+}
+
+// CommandHelpData is the data used to render the help of a command.
+type CommandHelpData struct {
+	App Application
+	Cmd *Command
+	// Args are the positional arguments of the command, if known.
+	Args []Arg
+	// FlagSet is the command's flags, nil if the command doesn't parse flags.
+	FlagSet *flag.FlagSet
+	// Flags is the description of the flags, formatted like
+	// flag.PrintDefaults.
+	Flags string
+	// ShowAdvancedTip is true when advanced flags were skipped.
+	ShowAdvancedTip bool
+	// Style is the style to use, nil if the output shouldn't be styled.
+	Style *Style
+}
+
+// DefaultUsageTemplate is the template used by TemplateRenderer to render
+// UsageData.
+const DefaultUsageTemplate = `{{.Title}}
+
+{{style "heading" "Usage:"}}  {{.Name}} [command] [arguments]
+
+{{style "heading" "Commands:"}}{{range .Commands}}
+  {{pad (style "command" .Name) $.WidestCmd}}  {{if .IsSection}}{{style "heading" .ShortDesc}}{{else}}{{wrap $.CmdIndent .ShortDesc}}{{end}}{{end}}
+
+{{if .HelpTopics}}{{style "heading" "Additional help topics:"}}{{range .HelpTopics}}
+  {{pad (style "command" .Name) $.WidestTopic}}  {{wrap $.TopicIndent .ShortDesc}}{{end}}
+
+{{end}}{{if .EnvVars}}{{style "heading" "Environment Variables:"}}{{range .EnvVars}}
+  {{pad .Name $.WidestEnvVar}}  {{wrap $.EnvVarIndent .Desc}}{{end}}
+
+{{end}}
+Use "{{.Name}} help [command]" for more information about a command.{{if .HelpTopics}}
+Use "{{.Name}} help [topic]" for more information about that topic.{{end}}{{if .ShowAdvancedTip}}
+Use "{{.Name}} help -advanced" to display all commands.{{end}}
+
+`
+
+// DefaultCommandHelpTemplate is the template used by TemplateRenderer to
+// render CommandHelpData.
+const DefaultCommandHelpTemplate = `{{.Cmd.LongDesc | trim | wrap 0 | wrapWithLines}}{{style "heading" "usage:"}}  {{.App.GetName}} {{.Cmd.UsageLine}}
+{{if .Cmd.Aliases}}{{style "heading" "aliases:"}}  {{join .Cmd.Aliases ", "}}
+{{end}}{{range .Args}}  {{.}}{{if .Type}} {{.Type}}{{end}}
+{{if .Desc}}    	{{.Desc}}
+{{end}}{{end}}{{.Flags}}{{if .ShowAdvancedTip}}
+Use "{{.App.GetName}} help -advanced {{.Cmd.Name}}" to display all flags.
+{{end}}{{if .Cmd.Examples}}
+{{style "heading" "examples:"}}{{range .Cmd.Examples}}
+  {{$.App.GetName}} {{.Command}}{{if .Desc}}
+    {{wrap 4 .Desc}}{{end}}{{end}}
+{{end}}`
+
+// TemplateRenderer is a HelpRenderer using text/template templates. The
+// functions returned by HelpFuncs are available in the templates.
+//
+// Its zero value renders the default help pages.
+type TemplateRenderer struct {
+	// UsageTemplate is executed on a *UsageData. DefaultUsageTemplate is used
+	// when empty.
+	UsageTemplate string
+	// CommandHelpTemplate is executed on a *CommandHelpData.
+	// DefaultCommandHelpTemplate is used when empty.
+	CommandHelpTemplate string
+	// HelpTopicTemplate is executed on a *HelpTopicData.
+	// DefaultHelpTopicTemplate is used when empty.
+	HelpTopicTemplate string
+	// Funcs are additional functions available in the templates. They override
+	// the ones returned by HelpFuncs.
+	Funcs template.FuncMap
+}
+
+// RenderUsage implements HelpRenderer.
+func (t *TemplateRenderer) RenderUsage(w io.Writer, d *UsageData) error {
+	text := t.UsageTemplate
+	if text == "" {
+		text = DefaultUsageTemplate
+	}
+	return t.execute(w, d.Style, text, d)
+}
+
+// RenderCommandHelp implements HelpRenderer.
+func (t *TemplateRenderer) RenderCommandHelp(w io.Writer, d *CommandHelpData) error {
+	text := t.CommandHelpTemplate
+	if text == "" {
+		text = DefaultCommandHelpTemplate
+	}
+	return t.execute(w, d.Style, text, d)
+}
+
+// RenderHelpTopic implements HelpTopicRenderer.
+func (t *TemplateRenderer) RenderHelpTopic(w io.Writer, d *HelpTopicData) error {
+	text := t.HelpTopicTemplate
+	if text == "" {
+		text = DefaultHelpTopicTemplate
+	}
+	return t.execute(w, d.Style, text, d)
+}
+
+func (t *TemplateRenderer) execute(w io.Writer, st *Style, text string, data interface{}) error {
+	tmpl := template.New("help").Funcs(HelpFuncs(w, st)).Funcs(t.Funcs)
+	if _, err := tmpl.Parse(text); err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+// HelpFuncs returns the functions used in the default help templates, for
+// output written to w and styled with st, which can be nil:
+//
+//   - trim trims the leading and trailing whitespace.
+//   - wrapWithLines appends two line feeds to non-empty text.
+//   - pad <text> <width> pads text with spaces up to width columns.
+//   - wrap <indent> <text> wraps text to the width of the terminal when
+//     starting at column indent, indenting the continuation lines.
+//   - style <kind> <text> styles text, see Style.Apply.
+//   - join <list> <sep> joins a list of strings with sep.
+func HelpFuncs(w io.Writer, st *Style) template.FuncMap {
+	width := terminalWidth(w)
+	return template.FuncMap{
+		"trim":          strings.TrimSpace,
+		"wrapWithLines": wrapWithLines,
+		"pad":           padRight,
+		"wrap": func(indent int, s string) string {
+			return wrapText(s, width, indent)
+		},
+		"style": st.Apply,
+		"join":  strings.Join,
+	}
+}
+
+// helpRendererFor returns the HelpRenderer to use for a.
+func helpRendererFor(a Application) HelpRenderer {
+	if h, ok := AppAs[HelpRendererApplication](a); ok {
+		if r := h.GetHelpRenderer(); r != nil {
+			return r
+		}
+	}
+	return &TemplateRenderer{}
+}
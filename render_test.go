@@ -0,0 +1,102 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+	"text/template"
+
+	"github.com/maruel/ut"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	a := &application{
+		DefaultApplication: DefaultApplication{
+			Name:  "App",
+			Title: "Title",
+			Commands: []*Command{
+				{
+					UsageLine:     "foo <bar>",
+					ShortDesc:     "foo desc",
+					Advanced:      true,
+					AdvancedFlags: []string{"x"},
+					CommandRun: func() CommandRun {
+						c := &command{}
+						c.Flags.Bool("x", false, "x flag")
+						return c
+					},
+				},
+				Section("Misc"),
+				CmdHelp,
+			},
+			HelpRenderer: &TemplateRenderer{
+				UsageTemplate: "{{brand .Name}}{{range .Commands}}{{if .IsSection}} [{{trim .ShortDesc}}]{{else}} {{.Name}}{{end}}{{end}}{{if .ShowAdvancedTip}} +{{end}}\n",
+				CommandHelpTemplate: "{{brand .App.GetName}} {{.Cmd.Name}}:{{range .Args}} {{.}}{{end}} " +
+					"{{.FlagSet.NFlag}}{{if .ShowAdvancedTip}} +{{end}}\n{{.Flags}}",
+				Funcs: template.FuncMap{
+					"brand": func(s string) string { return "** " + s + " **" },
+				},
+			},
+		},
+	}
+	ut.AssertEqual(t, 0, Run(a, []string{"help"}))
+	ut.AssertEqual(t, "** App ** [Misc] help +\n", a.out.String())
+	a.out.Reset()
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "-advanced"}))
+	ut.AssertEqual(t, "** App ** foo [Misc] help\n", a.out.String())
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "foo"}))
+	ut.AssertEqual(t, "** App ** foo: <bar> 0 +\n", a.err.String())
+	a.err.Reset()
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "-advanced", "foo"}))
+	ut.AssertEqual(t, "** App ** foo: <bar> 0\n  -x\tx flag\n", a.err.String())
+}
+
+type jsonRenderer struct{}
+
+func (jsonRenderer) RenderUsage(w io.Writer, d *UsageData) error {
+	_, err := fmt.Fprintf(w, "{\"name\": %q, \"commands\": %d}\n", d.Name, len(d.Commands))
+	return err
+}
+
+func (jsonRenderer) RenderCommandHelp(w io.Writer, d *CommandHelpData) error {
+	_, err := fmt.Fprintf(w, "{\"command\": %q}\n", d.Cmd.Name())
+	return err
+}
+
+func TestHelpRenderer(t *testing.T) {
+	a := &application{
+		DefaultApplication: DefaultApplication{
+			Name:         "App",
+			Commands:     []*Command{CmdHelp},
+			HelpRenderer: jsonRenderer{},
+		},
+	}
+	ut.AssertEqual(t, 0, Run(a, []string{"help"}))
+	ut.AssertEqual(t, "{\"name\": \"App\", \"commands\": 1}\n", a.out.String())
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "help"}))
+	ut.AssertEqual(t, "{\"command\": \"help\"}\n", a.err.String())
+}
+
+func TestHelpFuncs(t *testing.T) {
+	t.Setenv(ColumnsEnvVar, "10")
+	buf := bytes.Buffer{}
+	f := HelpFuncs(&buf, &Style{Flag: "33"})
+	wrap := f["wrap"].(func(int, string) string)
+	ut.AssertEqual(t, "aaa bbb\n  ccc", wrap(2, "aaa bbb ccc"))
+	style := f["style"].(func(StyleKind, string) string)
+	ut.AssertEqual(t, "\x1b[33m-x\x1b[0m", style(StyleFlag, "-x"))
+	ut.AssertEqual(t, "a\n\n", f["wrapWithLines"].(func(string) string)("a"))
+}
+
+func TestTemplateRenderer_Error(t *testing.T) {
+	r := &TemplateRenderer{UsageTemplate: "{{.Inexistant}}"}
+	buf := bytes.Buffer{}
+	ut.AssertEqual(t, true, r.RenderUsage(&buf, &UsageData{}) != nil)
+	r = &TemplateRenderer{UsageTemplate: "{{"}
+	ut.AssertEqual(t, true, r.RenderUsage(&buf, &UsageData{}) != nil)
+}
@@ -23,21 +23,22 @@ var askApplication = &subcommands.DefaultApplication{
 	},
 }
 
-var cmdAsk = &subcommands.Command{
+var cmdAsk = subcommands.AppCommand(subcommands.Command{
 	UsageLine: "ask <subcommand>",
 	ShortDesc: "asks questions",
 	LongDesc:  "Asks one of the known subquestion.",
-	CommandRun: func() subcommands.CommandRun {
-		c := &askRun{}
-		c.init()
-		app := sampleComplexApplication{askApplication, nil}
-		c.Flags.Usage = func() {
-			advanced := helpAdvanced != nil && helpAdvanced.String() == "true"
-			subcommands.Usage(os.Stderr, &app, advanced)
-		}
-		return c
-	},
-}
+	// The arguments are parsed by the inner application.
+	FreeForm: true,
+}, func() subcommands.TypedCommandRun[*sampleComplexApplication] {
+	c := &askRun{}
+	c.init()
+	app := sampleComplexApplication{askApplication, nil}
+	c.Flags.Usage = func() {
+		advanced := helpAdvanced != nil && helpAdvanced.String() == "true"
+		subcommands.Usage(os.Stderr, &app, advanced)
+	}
+	return c
+})
 
 type askRun struct {
 	commonFlags
@@ -54,8 +55,7 @@ func (a *askCommonFlags) parse(*sampleComplexApplication) error {
 	return nil
 }
 
-func (c *askRun) Run(a subcommands.Application, args []string, env subcommands.Env) int {
-	d := a.(*sampleComplexApplication)
+func (c *askRun) Run(a subcommands.Application, d *sampleComplexApplication, args []string, env subcommands.Env) int {
 	// Create an inner application.
 	app := sampleComplexApplication{askApplication, d.log}
 	return subcommands.Run(&app, args)
@@ -10,17 +10,16 @@ import (
 	"github.com/maruel/subcommands"
 )
 
-var cmdAskApple = &subcommands.Command{
+var cmdAskApple = subcommands.AppCommand(subcommands.Command{
 	UsageLine: "apple <options>",
 	ShortDesc: "asks for an apple",
 	LongDesc:  "Asks for an apple.",
-	CommandRun: func() subcommands.CommandRun {
-		c := &askAppleRun{}
-		c.init()
-		c.Flags.BoolVar(&c.direct, "direct", false, "Be more direct")
-		return c
-	},
-}
+}, func() subcommands.TypedCommandRun[*sampleComplexApplication] {
+	c := &askAppleRun{}
+	c.init()
+	c.Flags.BoolVar(&c.direct, "direct", false, "Be more direct")
+	return c
+})
 
 type askAppleRun struct {
 	askCommonFlags
@@ -39,12 +38,7 @@ func (c *askAppleRun) main(a *sampleComplexApplication) error {
 	return nil
 }
 
-func (c *askAppleRun) Run(a subcommands.Application, args []string, env subcommands.Env) int {
-	if len(args) != 0 {
-		fmt.Fprintf(a.GetErr(), "%s: Unknown arguments.\n", a.GetName())
-		return 1
-	}
-	d := a.(*sampleComplexApplication)
+func (c *askAppleRun) Run(a subcommands.Application, d *sampleComplexApplication, args []string, env subcommands.Env) int {
 	if err := c.main(d); err != nil {
 		fmt.Fprintf(a.GetErr(), "%s: %s\n", a.GetName(), err)
 		return 1
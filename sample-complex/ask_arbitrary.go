@@ -13,7 +13,7 @@ import (
 )
 
 var cmdAskArbitrary = &subcommands.Command{
-	UsageLine: "arbitrary <anything>",
+	UsageLine: "arbitrary <anything>...",
 	ShortDesc: "asks for anything you want",
 	LongDesc:  "Asks for arbitrary arguments.",
 	CommandRun: func() subcommands.CommandRun {
@@ -28,10 +28,6 @@ type askArbitraryRun struct {
 func (c *askArbitraryRun) GetFlags() *flag.FlagSet { return nil }
 
 func (c *askArbitraryRun) Run(a subcommands.Application, args []string, env subcommands.Env) int {
-	if len(args) == 0 {
-		fmt.Fprintf(a.GetErr(), "%s: expected a question.", a.GetName())
-		return 1
-	}
 	if last := args[len(args)-1]; !strings.HasSuffix(last, "?") {
 		fmt.Fprintf(a.GetErr(), "%s: expected a question ending with `?`.", a.GetName())
 		return 1
@@ -12,42 +12,31 @@ import (
 	"github.com/maruel/subcommands"
 )
 
-var cmdAskBeer = &subcommands.Command{
+// cmdAskBeer is defined from the tags on askBeerRun; see
+// subcommands.FromStruct.
+var cmdAskBeer = subcommands.FromStruct(subcommands.Command{
 	UsageLine: "beer <options>",
 	ShortDesc: "asks for beer",
 	LongDesc:  "Asks for beer.",
 	Advanced:  true,
-	CommandRun: func() subcommands.CommandRun {
-		c := &askBeerRun{}
-		c.init()
-		c.Flags.StringVar(&c.brand, "brand", "", "Which brand do you want?")
-		return c
-	},
-}
+}, func() subcommands.TaggedRun {
+	return &askBeerRun{}
+})
 
 type askBeerRun struct {
-	askCommonFlags
-	brand string
+	Brand string `flag:"brand" usage:"Which brand do you want?"`
 }
 
-func (c *askBeerRun) main(a *sampleComplexApplication) error {
-	if err := c.parse(a); err != nil {
-		return err
-	}
-	if c.brand != "" && strings.ToLower(c.brand) != "unibroue" {
-		fmt.Fprintf(a.GetOut(), "%q sounds interesting but we are partial to Unibroue.\n", c.brand)
+func (c *askBeerRun) main(a subcommands.Application) error {
+	if c.Brand != "" && strings.ToLower(c.Brand) != "unibroue" {
+		fmt.Fprintf(a.GetOut(), "%q sounds interesting but we are partial to Unibroue.\n", c.Brand)
 		return nil
 	}
 	return errors.New("it's a BYOB part")
 }
 
 func (c *askBeerRun) Run(a subcommands.Application, args []string, env subcommands.Env) int {
-	if len(args) != 0 {
-		fmt.Fprintf(a.GetErr(), "%s: Unknown arguments.\n", a.GetName())
-		return 1
-	}
-	d := a.(*sampleComplexApplication)
-	if err := c.main(d); err != nil {
+	if err := c.main(a); err != nil {
 		fmt.Fprintf(a.GetErr(), "%s: %s\n", a.GetName(), err)
 		return 1
 	}
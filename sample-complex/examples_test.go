@@ -0,0 +1,22 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"github.com/maruel/subcommands/subcommandstest"
+)
+
+func init() {
+	subcommandstest.DisableLogOutput()
+}
+
+func TestExamples(t *testing.T) {
+	s := &sampleComplexApplication{application, log.New(io.Discard, "", 0)}
+	subcommandstest.CheckExamples(t, s)
+}
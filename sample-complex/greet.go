@@ -6,42 +6,45 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log"
 
 	"github.com/maruel/subcommands"
 )
 
-var cmdGreet = &subcommands.Command{
+var cmdGreet = subcommands.AppCommand(subcommands.Command{
 	UsageLine: "greet <who>",
 	ShortDesc: "greets someone",
 	LongDesc:  "Greets someone. This command has no specific option except the common ones.",
-	CommandRun: func() subcommands.CommandRun {
-		c := &greetRun{}
-		c.init()
-		return c
+	Args: []subcommands.Arg{
+		{Name: "who", Desc: "Person to greet."},
 	},
-}
+	Examples: []subcommands.Example{
+		{Command: "greet bob", Desc: "Greets bob."},
+		{Command: `greet "Mr. Bob"`, Desc: "Names with spaces must be quoted."},
+		{Command: "greet", Desc: "Fails since the person to greet is missing.", ExitCode: 2},
+	},
+}, func() subcommands.TypedCommandRun[*sampleComplexApplication] {
+	c := &greetRun{}
+	c.init()
+	return c
+})
 
 type greetRun struct {
 	commonFlags
 }
 
-func (c *greetRun) main(a *sampleComplexApplication, who, greeting string) error {
+func (c *greetRun) main(a *sampleComplexApplication, out io.Writer, who, greeting string) error {
 	if err := c.parse(a); err != nil {
 		return err
 	}
 	log.Printf("Unnecessary logging, use -verbose to see it")
-	fmt.Fprintf(a.GetOut(), "%s %s!\n", greeting, who)
+	fmt.Fprintf(out, "%s %s!\n", greeting, who)
 	return nil
 }
 
-func (c *greetRun) Run(a subcommands.Application, args []string, env subcommands.Env) int {
-	if len(args) != 1 {
-		fmt.Fprintf(a.GetErr(), "%s: Can only greet one person at a time.\n", a.GetName())
-		return 1
-	}
-	d := a.(*sampleComplexApplication)
-	if err := c.main(d, args[0], env["GREET_STYLE"].Value); err != nil {
+func (c *greetRun) Run(a subcommands.Application, d *sampleComplexApplication, args []string, env subcommands.Env) int {
+	if err := c.main(d, a.GetOut(), args[0], env["GREET_STYLE"].Value); err != nil {
 		fmt.Fprintf(a.GetErr(), "%s: %s\n", a.GetName(), err)
 		return 1
 	}
@@ -38,6 +38,8 @@ var application = &subcommands.DefaultApplication{
 			ShortDesc: `If set to "1", shows dream while sleeping.`,
 		},
 	},
+	// Warn about misspelled variables like GREET_STYL.
+	EnvVarCheck: &subcommands.EnvVarCheck{Prefixes: []string{"GREET_", "VERBOSE_"}},
 }
 
 // cmdHelp overrides subcommands.CmdHelp to enable capture of the -advanced
@@ -12,16 +12,15 @@ import (
 	"github.com/maruel/subcommands"
 )
 
-var cmdSleep = &subcommands.Command{
+var cmdSleep = subcommands.AppCommand(subcommands.Command{
 	UsageLine: "sleep <options>",
 	ShortDesc: "sleeps for some time",
 	LongDesc:  "Sleeps for some time, as desired.",
-	CommandRun: func() subcommands.CommandRun {
-		c := &sleepRun{}
-		c.Flags.DurationVar(&c.duration, "duration", time.Second, "Duration")
-		return c
-	},
-}
+}, func() subcommands.TypedCommandRun[*sampleComplexApplication] {
+	c := &sleepRun{}
+	c.Flags.DurationVar(&c.duration, "duration", time.Second, "Duration")
+	return c
+})
 
 type sleepRun struct {
 	// This command doesn't implement the common flags.
@@ -48,12 +47,7 @@ func (c *sleepRun) main(a *sampleComplexApplication, dream bool) error {
 	return nil
 }
 
-func (c *sleepRun) Run(a subcommands.Application, args []string, env subcommands.Env) int {
-	if len(args) != 0 {
-		fmt.Fprintf(a.GetErr(), "%s: Unsupported arguments.\n", a.GetName())
-		return 1
-	}
-	d := a.(*sampleComplexApplication)
+func (c *sleepRun) Run(a subcommands.Application, d *sampleComplexApplication, args []string, env subcommands.Env) int {
 	// This main() wrapping simplifies the surfacing of errors into printing to
 	// stderr then exiting with 1.
 	if err := c.main(d, env["VERBOSE_DREAMS"].Value == "1"); err != nil {
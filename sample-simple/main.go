@@ -28,6 +28,7 @@ var application = &subcommands.DefaultApplication{
 		cmdGreet,
 		cmdSleep,
 		subcommands.CmdHelp,
+		subcommands.CmdVersion,
 	},
 }
 
@@ -35,6 +36,9 @@ var cmdGreet = &subcommands.Command{
 	UsageLine: "greet <who>",
 	ShortDesc: "greets someone",
 	LongDesc:  "Greets someone. This command has no specific option except the common ones.",
+	Args: []subcommands.Arg{
+		{Name: "who", Desc: "Person to greet."},
+	},
 	CommandRun: func() subcommands.CommandRun {
 		return &greetRun{}
 	},
@@ -45,10 +49,7 @@ type greetRun struct {
 }
 
 func (c *greetRun) Run(a subcommands.Application, args []string, env subcommands.Env) int {
-	if len(args) != 1 {
-		fmt.Fprintf(os.Stderr, "%s: Can only greet one person at a time.\n", a.GetName())
-		return 1
-	}
+	// args has already been validated against cmdGreet.Args.
 	fmt.Printf("Hi %s!\n", args[0])
 	return 0
 }
@@ -57,6 +58,8 @@ var cmdSleep = &subcommands.Command{
 	UsageLine: "sleep <options>",
 	ShortDesc: "sleeps for some time",
 	LongDesc:  "Sleeps for some time, as desired.",
+	// Run enforces that -duration is specified.
+	RequiredFlags: []string{"duration"},
 	CommandRun: func() subcommands.CommandRun {
 		c := &sleepRun{}
 		c.Flags.IntVar(&c.duration, "duration", 0, "Duration in seconds")
@@ -70,12 +73,8 @@ type sleepRun struct {
 }
 
 func (c *sleepRun) Run(a subcommands.Application, args []string, env subcommands.Env) int {
-	if len(args) != 0 {
-		fmt.Fprintf(os.Stderr, "%s: Unsupported arguments.\n", a.GetName())
-		return 1
-	}
 	if c.duration <= 0 {
-		fmt.Fprintf(os.Stderr, "%s: -duration is required.\n", a.GetName())
+		fmt.Fprintf(os.Stderr, "%s: -duration must be positive.\n", a.GetName())
 		return 1
 	}
 	log.Printf("Simulating sleeping for %ds.\n", c.duration)
@@ -0,0 +1,214 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ScriptFile is the content of a file loaded by LoadScriptCommands.
+//
+// Example:
+//
+//	{
+//	  "commands": [
+//	    {
+//	      "usage_line": "deploy-staging [-force] <version>",
+//	      "short_desc": "deploys a version to staging",
+//	      "flags": [{"name": "force", "usage": "skips checks", "bool": true}],
+//	      "env": {"TARGET": "staging"},
+//	      "run": "./deploy.sh {{if .Flags.force}}--force {{end}}{{index .Args 0 | quote}}"
+//	    }
+//	  ]
+//	}
+type ScriptFile struct {
+	Commands []ScriptCommand `json:"commands"`
+}
+
+// ScriptCommand is a command running a shell command, defined without
+// writing Go.
+type ScriptCommand struct {
+	// UsageLine is the Command.UsageLine and must start with the command name.
+	UsageLine string   `json:"usage_line"`
+	ShortDesc string   `json:"short_desc"`
+	LongDesc  string   `json:"long_desc"`
+	Advanced  bool     `json:"advanced"`
+	Aliases   []string `json:"aliases"`
+	// Args are the positional arguments. When empty, they are derived from
+	// UsageLine.
+	Args  []PluginArg  `json:"args"`
+	Flags []PluginFlag `json:"flags"`
+	// Env are environment variables set for the shell command. They override
+	// the environment and are declared in Command.EnvVars, so they are listed
+	// with the application's environment variables.
+	Env map[string]string `json:"env"`
+	// Run is a text/template rendering the shell command, run with "sh -c",
+	// or "cmd /S /C" on Windows.
+	//
+	// The template receives a ScriptData and has the function "quote" to
+	// quote a value for the shell. On POSIX shells, the positional arguments
+	// are also available as "$@".
+	//
+	// On Windows, "quote" only protects spaces, double quotes and the
+	// characters like & and |. cmd still expands %VAR% in quoted values, and
+	// !VAR! when delayed expansion is enabled, so values containing % or !
+	// must not come from untrusted input.
+	Run string `json:"run"`
+}
+
+// ScriptData is the data passed to the template of ScriptCommand.Run.
+type ScriptData struct {
+	// Args are the positional arguments.
+	Args []string
+	// Flags are the values of the flags, by name. The values of bool flags are
+	// bool, the others are string.
+	Flags map[string]any
+	// Env are the values of the application's environment variables,
+	// including their defaults.
+	Env map[string]string
+}
+
+// LoadScriptCommands loads the ScriptFile at path, in JSON, and returns its
+// commands, to be added to the application's commands. They appear in help,
+// completion and docs like commands written in Go.
+//
+// Only JSON is supported, to not add a YAML dependency to this package.
+func LoadScriptCommands(path string) ([]*Command, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cmds, err := ParseScriptCommands(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cmds, nil
+}
+
+// ParseScriptCommands parses a ScriptFile in JSON and returns its commands.
+// Unknown fields are rejected to catch typos.
+func ParseScriptCommands(data []byte) ([]*Command, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	f := ScriptFile{}
+	if err := d.Decode(&f); err != nil {
+		return nil, err
+	}
+	out := make([]*Command, 0, len(f.Commands))
+	for i := range f.Commands {
+		c, err := f.Commands[i].command()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// scriptFuncs are the functions available in ScriptCommand.Run.
+var scriptFuncs = template.FuncMap{"quote": shellQuote}
+
+// command returns the Command running s.
+func (s *ScriptCommand) command() (*Command, error) {
+	c := &Command{
+		UsageLine: s.UsageLine,
+		ShortDesc: s.ShortDesc,
+		LongDesc:  s.LongDesc,
+		Advanced:  s.Advanced,
+		Aliases:   s.Aliases,
+	}
+	name := c.Name()
+	if name == "" {
+		return nil, errors.New("command without usage_line")
+	}
+	if s.Run == "" {
+		return nil, fmt.Errorf("command %s: run is required", name)
+	}
+	t, err := template.New(name).Funcs(scriptFuncs).Option("missingkey=error").Parse(s.Run)
+	if err != nil {
+		return nil, fmt.Errorf("command %s: %w", name, err)
+	}
+	for _, a := range s.Args {
+		c.Args = append(c.Args, Arg{Name: a.Name, Desc: a.Desc, Optional: a.Optional, Variadic: a.Variadic, Choices: a.Choices})
+	}
+	flags := s.Flags
+	env := make([]string, 0, len(s.Env))
+	for k, v := range s.Env {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	if len(s.Env) != 0 {
+		c.EnvVars = make(map[string]EnvVarDefinition, len(s.Env))
+		for k, v := range s.Env {
+			c.EnvVars[k] = EnvVarDefinition{ShortDesc: fmt.Sprintf("Set to %q by the command %s.", v, name)}
+		}
+	}
+	c.CommandRun = func() CommandRun {
+		r := &scriptRun{t: t, env: env, values: map[string]*pluginFlagValue{}}
+		r.Flags.Init(name, flag.ContinueOnError)
+		for _, f := range flags {
+			v := &pluginFlagValue{flag: f, value: f.Default}
+			r.values[f.Name] = v
+			r.Flags.Var(v, f.Name, f.Usage)
+		}
+		return r
+	}
+	return c, nil
+}
+
+// scriptRun runs a ScriptCommand.
+type scriptRun struct {
+	CommandRunBase
+	t      *template.Template
+	env    []string
+	values map[string]*pluginFlagValue
+}
+
+func (r *scriptRun) Run(a Application, args []string, env Env) int {
+	d := ScriptData{Args: args, Flags: map[string]any{}, Env: map[string]string{}}
+	for k, v := range r.values {
+		if v.flag.Bool {
+			// An unset flag without default is "", which is false.
+			d.Flags[k], _ = strconv.ParseBool(v.value)
+		} else {
+			d.Flags[k] = v.value
+		}
+	}
+	for k, v := range env {
+		d.Env[k] = v.Value
+	}
+	b := strings.Builder{}
+	if err := r.t.Execute(&b, &d); err != nil {
+		fmt.Fprintf(a.GetErr(), "%s %s\n", errorPrefix(a), err)
+		return 1
+	}
+	return runCmd(a, scriptCmd(b.String(), r.t.Name(), args), r.env)
+}
+
+// shellQuote quotes s for a POSIX shell, or for cmd on Windows.
+//
+// cmd has no way to escape % inside double quotes, so on Windows environment
+// variable references in s are still expanded. See ScriptCommand.Run.
+func shellQuote(s string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@,+%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
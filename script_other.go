@@ -0,0 +1,15 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+//go:build !windows
+
+package subcommands
+
+import "os/exec"
+
+// scriptCmd returns the command running script with "sh -c". name is $0 and
+// args are the positional parameters.
+func scriptCmd(script, name string, args []string) *exec.Cmd {
+	return exec.Command("sh", append([]string{"-c", script, name}, args...)...)
+}
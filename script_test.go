@@ -0,0 +1,129 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+const testScripts = `{
+  "commands": [
+    {
+      "usage_line": "deploy-staging [-force] [-region <region>] <version>",
+      "short_desc": "deploys a version to staging",
+      "long_desc": "Deploys a version to the staging environment.",
+      "aliases": ["ds"],
+      "args": [{"name": "version", "desc": "Version to deploy."}],
+      "flags": [
+        {"name": "force", "usage": "skips checks", "bool": true},
+        {"name": "region", "usage": "region to deploy to", "default": "us", "choices": ["us", "eu"]}
+      ],
+      "env": {"TARGET": "staging"},
+      "run": "echo deploying {{index .Args 0 | quote}} to $TARGET in {{.Flags.region}}{{if .Flags.force}} with force{{end}} as {{.Env.GREET_STYLE}}; echo \"$1\" >&2; exit 3"
+    },
+    {
+      "usage_line": "hello",
+      "short_desc": "says hello",
+      "run": "echo hello"
+    }
+  ]
+}`
+
+func TestScriptCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	p := filepath.Join(t.TempDir(), "commands.json")
+	ut.AssertEqual(t, nil, os.WriteFile(p, []byte(testScripts), 0o644))
+	scripts, err := LoadScriptCommands(p)
+	ut.AssertEqual(t, nil, err)
+	ut.AssertEqual(t, 2, len(scripts))
+	newApp := func() *application {
+		return &application{
+			DefaultApplication: DefaultApplication{
+				Name:     "App",
+				Commands: append([]*Command{CmdHelp}, scripts...),
+				EnvVars:  map[string]EnvVarDefinition{"GREET_STYLE": {Default: "Hi"}},
+			},
+		}
+	}
+
+	a := newApp()
+	ut.AssertEqual(t, 3, Run(a, []string{"deploy-staging", "-force", "-region", "eu", "v1 beta"}))
+	ut.AssertEqual(t, "deploying v1 beta to staging in eu with force as Hi\n", a.out.String())
+	ut.AssertEqual(t, "v1 beta\n", a.err.String())
+
+	a = newApp()
+	ut.AssertEqual(t, 3, Run(a, []string{"ds", "v1"}))
+	ut.AssertEqual(t, "deploying v1 to staging in us as Hi\n", a.out.String())
+
+	a = newApp()
+	ut.AssertEqual(t, 2, Run(a, []string{"deploy-staging"}))
+	ut.AssertEqual(t, "", a.out.String())
+
+	a = newApp()
+	ut.AssertEqual(t, 0, Run(a, []string{"hello"}))
+	ut.AssertEqual(t, "hello\n", a.out.String())
+
+	a = newApp()
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "deploy-staging"}))
+	expected := "Deploys a version to the staging environment.\n\n" +
+		"usage:  App deploy-staging [-force] [-region <region>] <version>\n" +
+		"aliases:  ds\n" +
+		"  <version>\n" +
+		"    \tVersion to deploy.\n" +
+		"  -force\n" +
+		"    \tskips checks\n" +
+		"  -region value\n" +
+		"    \tregion to deploy to (default us)\n"
+	ut.AssertEqual(t, expected, a.err.String())
+
+	ut.AssertEqual(t, []string{"eu"}, Complete(a, []string{"deploy-staging", "-region", "e"}))
+	ut.AssertEqual(t, []string{"deploy-staging"}, Complete(a, []string{"dep"}))
+
+	b := strings.Builder{}
+	ut.AssertEqual(t, nil, WriteMarkdown(&b, a))
+	ut.AssertEqual(t, true, strings.Contains(b.String(), "### deploy-staging\n\ndeploys a version to staging\n"))
+	ut.AssertEqual(t, true, strings.Contains(b.String(), "- `TARGET`: Set to \"staging\" by the command deploy-staging.\n"))
+
+	a = newApp()
+	ut.AssertEqual(t, 0, Run(a, []string{"help"}))
+	ut.AssertEqual(t, true, strings.Contains(a.out.String(), "  TARGET       Set to \"staging\" by the command deploy-staging.\n"))
+}
+
+func TestParseScriptCommandsErrors(t *testing.T) {
+	data := []struct {
+		in  string
+		err string
+	}{
+		{`{"commands": [{"usage_line": "a", "run": "true", "typo": 1}]}`, `json: unknown field "typo"`},
+		{`{"commands": [{"run": "true"}]}`, "command without usage_line"},
+		{`{"commands": [{"usage_line": "a"}]}`, "command a: run is required"},
+		{`{"commands": [{"usage_line": "a", "run": "{{"}]}`, "command a: template: a:1: unclosed action"},
+	}
+	for i, line := range data {
+		_, err := ParseScriptCommands([]byte(line.in))
+		ut.AssertEqualIndex(t, i, line.err, err.Error())
+	}
+	_, err := LoadScriptCommands(filepath.Join(t.TempDir(), "missing.json"))
+	ut.AssertEqual(t, true, err != nil)
+}
+
+func TestShellQuote(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX quoting")
+	}
+	ut.AssertEqual(t, "v1.2", shellQuote("v1.2"))
+	ut.AssertEqual(t, "''", shellQuote(""))
+	ut.AssertEqual(t, "'a b'", shellQuote("a b"))
+	ut.AssertEqual(t, `'it'\''s'`, shellQuote("it's"))
+	ut.AssertEqual(t, "'$HOME'", shellQuote("$HOME"))
+}
@@ -0,0 +1,24 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+//go:build windows
+
+package subcommands
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// scriptCmd returns the command running script with "cmd /S /C". name and
+// args are not passed, cmd has no positional parameters.
+//
+// The command line is set as is, since cmd doesn't parse it with the rules
+// exec.Command escapes the arguments for. With /S, cmd strips the outer
+// quotes and runs the rest unchanged.
+func scriptCmd(script, name string, args []string) *exec.Cmd {
+	cmd := exec.Command("cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd /S /C "` + script + `"`}
+	return cmd
+}
@@ -0,0 +1,235 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SearchKind is the kind of item found by Search.
+type SearchKind int
+
+const (
+	// SearchCommand is a command.
+	SearchCommand SearchKind = iota
+	// SearchFlag is a flag of a command.
+	SearchFlag
+	// SearchEnvVar is an environment variable.
+	SearchEnvVar
+	// SearchHelpTopic is a help topic.
+	SearchHelpTopic
+)
+
+// SearchResult is an item matching a Search.
+type SearchResult struct {
+	Kind SearchKind
+	// Name is the name of the item, e.g. "deploy -force" for the flag -force of
+	// the command deploy.
+	Name string
+	// Command is the command, or the command of the flag. It is nil for
+	// environment variables and help topics.
+	Command *Command
+	// Matched is the number of keywords matched.
+	Matched int
+	// Score is the relevance of the item. Higher is better.
+	Score int
+	// Snippet is an excerpt of the best matching text.
+	Snippet string
+}
+
+// searchField is a text searched with a weight.
+type searchField struct {
+	text   string
+	weight int
+}
+
+// Search returns the commands, flags, environment variables and help topics of
+// a matching the keywords in query, most relevant first.
+//
+// Keywords are matched case-insensitively in the names, UsageLine, ShortDesc,
+// LongDesc and flag usage strings. Matches in names rank higher than in
+// descriptions and items matching more keywords rank first. Advanced commands,
+// flags and environment variables are only searched if includeAdvanced is
+// true.
+func Search(a Application, query string, includeAdvanced bool) []SearchResult {
+	keywords := strings.Fields(strings.ToLower(query))
+	if len(keywords) == 0 {
+		return nil
+	}
+	var out []SearchResult
+	add := func(r SearchResult, summary string, fields ...searchField) {
+		if r.Matched, r.Score, r.Snippet = matchFields(keywords, summary, fields); r.Matched != 0 {
+			out = append(out, r)
+		}
+	}
+	cmds := loadCommands(a.GetCommands()...)
+	for _, c := range cmds {
+		if c.isSection || (c.Advanced && !includeAdvanced) {
+			continue
+		}
+		add(SearchResult{Kind: SearchCommand, Name: c.Name(), Command: c}, c.ShortDesc,
+			searchField{c.Name(), 10}, searchField{c.UsageLine, 4}, searchField{c.ShortDesc, 3}, searchField{c.LongDesc, 1})
+		if c.CommandRun == nil {
+			continue
+		}
+		f := c.CommandRun().GetFlags()
+		if f == nil {
+			continue
+		}
+		f.VisitAll(func(fl *flag.Flag) {
+			if _, ok := c.DeprecatedFlags[fl.Name]; ok {
+				return
+			}
+			if !includeAdvanced && slices.Contains(c.AdvancedFlags, fl.Name) {
+				return
+			}
+			add(SearchResult{Kind: SearchFlag, Name: c.Name() + " -" + fl.Name, Command: c}, fl.Usage,
+				searchField{fl.Name, 6}, searchField{fl.Usage, 2})
+		})
+	}
+	envVars := allEnvVars(a)
+	for _, e := range sortedEnvVars(a) {
+		if envVars[e.Name].Advanced && !includeAdvanced {
+			continue
+		}
+		add(SearchResult{Kind: SearchEnvVar, Name: e.Name}, e.ShortDesc,
+			searchField{e.Name, 6}, searchField{e.ShortDesc, 2})
+	}
+	for _, t := range getHelpTopics(a) {
+		add(SearchResult{Kind: SearchHelpTopic, Name: t.Name}, t.ShortDesc,
+			searchField{t.Name, 10}, searchField{t.ShortDesc, 3}, searchField{t.LongDesc, 1})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Matched != out[j].Matched {
+			return out[i].Matched > out[j].Matched
+		}
+		return out[i].Score > out[j].Score
+	})
+	return out
+}
+
+// matchFields returns the number of keywords found in fields, the score and
+// a snippet of the best matching field. The first field is the name.
+//
+// summary is used as the snippet when only the name matched.
+func matchFields(keywords []string, summary string, fields []searchField) (matched, score int, snippet string) {
+	fieldScores := make([]int, len(fields))
+	for _, k := range keywords {
+		found := false
+		for i, f := range fields {
+			if n := strings.Count(strings.ToLower(f.text), k); n != 0 {
+				found = true
+				// Repeated occurrences add a little bit of relevance.
+				s := f.weight + min(n-1, 2)
+				score += s
+				fieldScores[i] += s
+			}
+		}
+		if found {
+			matched++
+		}
+	}
+	// Use the most detailed matching description for the snippet.
+	for i := len(fields) - 1; i > 0; i-- {
+		if fieldScores[i] != 0 {
+			return matched, score, makeSnippet(fields[i].text, keywords)
+		}
+	}
+	return matched, score, makeSnippet(summary, nil)
+}
+
+// snippetLen is the approximate maximum length of a snippet.
+const snippetLen = 60
+
+// makeSnippet returns the line of text containing the first keyword found,
+// shortened around it if too long.
+func makeSnippet(text string, keywords []string) string {
+	text = strings.TrimSpace(text)
+	lower := strings.ToLower(text)
+	pos := -1
+	for _, k := range keywords {
+		if i := strings.Index(lower, k); i >= 0 && (pos < 0 || i < pos) {
+			pos = i
+		}
+	}
+	if len(lower) != len(text) || pos < 0 {
+		// Case mapping changed the byte offsets.
+		pos = 0
+	}
+	// Only keep the line with the match.
+	start := strings.LastIndexByte(text[:pos], '\n') + 1
+	end := len(text)
+	if i := strings.IndexByte(text[pos:], '\n'); i >= 0 {
+		end = pos + i
+	}
+	line := text[start:end]
+	pos -= start
+	prefix, suffix := "", ""
+	if len(line) > snippetLen {
+		from := 0
+		if pos > snippetLen/3 {
+			from = strings.IndexByte(line[pos-snippetLen/3:], ' ')
+			if from < 0 || from > pos {
+				from = pos - snippetLen/3
+			} else {
+				from += pos - snippetLen/3 + 1
+			}
+			prefix = "..."
+		}
+		to := len(line)
+		if to-from > snippetLen {
+			to = from + strings.LastIndexByte(line[from:from+snippetLen], ' ')
+			if to <= from {
+				to = from + snippetLen
+			}
+			suffix = "..."
+		}
+		line = strings.ToValidUTF8(line[from:to], "")
+	}
+	return prefix + line + suffix
+}
+
+// maxSearchResults is the maximum number of results printed by
+// "help -search".
+const maxSearchResults = 10
+
+// printSearch prints the results of Search to out.
+func printSearch(out io.Writer, a Application, query string, includeAdvanced bool) {
+	results := Search(a, query, includeAdvanced)
+	if len(results) == 0 {
+		fmt.Fprintf(out, "No match for %q.\n", query)
+		return
+	}
+	if len(results) > maxSearchResults {
+		results = results[:maxSearchResults]
+	}
+	st := styleFor(a, out)
+	labels := make([]string, len(results))
+	widest := 0
+	for i, r := range results {
+		switch r.Kind {
+		case SearchEnvVar:
+			labels[i] = "$" + r.Name
+		case SearchHelpTopic:
+			labels[i] = r.Name + " (topic)"
+		default:
+			labels[i] = r.Name
+		}
+		if w := displayWidth(labels[i]); w > widest {
+			widest = w
+		}
+	}
+	width := terminalWidth(out)
+	for i, r := range results {
+		label := padRight(st.Apply(StyleCommand, labels[i]), widest)
+		fmt.Fprintf(out, "  %s  %s\n", label, wrapText(r.Snippet, width, widest+4))
+	}
+	fmt.Fprintf(out, "\nUse \"%s help [command]\" for more information about a command.\n", a.GetName())
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func searchApp() *application {
+	return &application{
+		DefaultApplication: DefaultApplication{
+			Name: "App",
+			Commands: []*Command{
+				CmdHelp,
+				Section("Release"),
+				{
+					UsageLine: "deploy <env>",
+					ShortDesc: "deploys the application",
+					LongDesc:  "Deploys the application to an environment.\nThe configuration is read from the config file.",
+					CommandRun: func() CommandRun {
+						c := &command{}
+						c.Flags.Bool("force", false, "deploy even if the checks fail")
+						c.Flags.Bool("old", false, "old deploy mode")
+						c.Flags.Bool("secret", false, "secret deploy mode")
+						return c
+					},
+					DeprecatedFlags: map[string]string{"old": ""},
+					AdvancedFlags:   []string{"secret"},
+				},
+				{
+					UsageLine: "rollback",
+					ShortDesc: "reverts the last deployment",
+					CommandRun: func() CommandRun {
+						return &command{}
+					},
+				},
+				{
+					UsageLine: "nuke",
+					ShortDesc: "deletes everything that was deployed",
+					Advanced:  true,
+				},
+			},
+			EnvVars: map[string]EnvVarDefinition{
+				"DEPLOY_TOKEN": {ShortDesc: "token used to authenticate"},
+				"DEBUG":        {ShortDesc: "enables debugging", Advanced: true},
+			},
+			HelpTopics: []*HelpTopic{
+				{Name: "config", ShortDesc: "configuration file format", LongDesc: "The config file describes where to deploy."},
+			},
+		},
+	}
+}
+
+func TestSearch(t *testing.T) {
+	a := searchApp()
+	var names []string
+	for _, r := range Search(a, "Deploy", false) {
+		names = append(names, r.Name)
+	}
+	ut.AssertEqual(t, []string{"deploy", "DEPLOY_TOKEN", "rollback", "deploy -force", "config"}, names)
+
+	names = nil
+	for _, r := range Search(a, "deploy config", true) {
+		names = append(names, r.Name)
+	}
+	ut.AssertEqual(t, []string{"deploy", "config", "DEPLOY_TOKEN", "rollback", "nuke", "deploy -force", "deploy -secret"}, names)
+	ut.AssertEqual(t, 0, len(Search(a, "  ", false)))
+}
+
+func TestCmdHelp_Search(t *testing.T) {
+	a := searchApp()
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "-search", "deploy"}))
+	expected := "" +
+		"  deploy          Deploys the application to an environment.\n" +
+		"  $DEPLOY_TOKEN   token used to authenticate\n" +
+		"  rollback        reverts the last deployment\n" +
+		"  deploy -force   deploy even if the checks fail\n" +
+		"  config (topic)  The config file describes where to deploy.\n" +
+		"\n" +
+		"Use \"App help [command]\" for more information about a command.\n"
+	ut.AssertEqual(t, expected, a.out.String())
+
+	a = searchApp()
+	ut.AssertEqual(t, 0, Run(a, []string{"help", "-search", "inexistant"}))
+	ut.AssertEqual(t, "No match for \"inexistant\".\n", a.out.String())
+
+	a = searchApp()
+	ut.AssertEqual(t, 2, Run(a, []string{"help", "-search"}))
+	ut.AssertEqual(t, "App: -search requires keywords\n\nRun 'App help help' for usage.\n", a.err.String())
+}
+
+func TestMakeSnippet(t *testing.T) {
+	data := []struct {
+		text     string
+		keywords []string
+		expected string
+	}{
+		{"", nil, ""},
+		{"short text", []string{"text"}, "short text"},
+		{"first line\nsecond line with match\nthird", []string{"match"}, "second line with match"},
+		{
+			"This is a very long line of text that goes on and on until the keyword appears near the end of it",
+			[]string{"keyword"},
+			"...and on until the keyword appears near the end of it",
+		},
+		{
+			"The keyword appears at the beginning of this very long line that goes on and on forever",
+			[]string{"keyword"},
+			"The keyword appears at the beginning of this very long line...",
+		},
+	}
+	for i, line := range data {
+		ut.AssertEqualIndex(t, i, line.expected, makeSnippet(line.text, line.keywords))
+	}
+}
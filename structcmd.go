@@ -0,0 +1,411 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// TaggedRun is implemented by the structs used with FromStruct.
+//
+// When Run is called, the struct's tagged fields have been populated from the
+// command line.
+type TaggedRun interface {
+	Run(a Application, args []string, env Env) int
+}
+
+// FromStruct returns a copy of c with CommandRun set to create the struct
+// returned by newRun and register its tagged fields.
+//
+// newRun must return a pointer to a struct. The following tags are supported
+// on exported fields:
+//
+//   - `flag:"name"` registers the field as the flag -name. The field's value
+//     when returned by newRun is the flag's default value.
+//   - `usage:"..."` is the flag's or argument's usage string.
+//   - `env:"NAME"` uses the environment variable NAME, when set, as the flag's
+//     value unless the flag is specified on the command line. NAME is added
+//     to Command.EnvVars.
+//   - `required:"true"` adds the flag to Command.RequiredFlags.
+//   - `advanced:"true"` adds the flag to Command.AdvancedFlags.
+//   - `arg:"name"` populates the field with a positional argument, in the
+//     order of the fields. The argument is variadic if the field is a slice.
+//   - `optional:"true"` makes the positional argument optional.
+//
+// Flags defined in the struct's own FlagSet, e.g. by embedding CommandRunBase
+// and calling Flags.BoolVar in newRun, are registered too.
+//
+// Flags can be of type string, bool, int, int64, uint, uint64, float64,
+// time.Duration, []string, []int, map[string]string or any type whose pointer
+// implements flag.Value. Positional arguments can be of type string, bool,
+// integers, float64, time.Duration, a slice of these or any type whose
+// pointer implements flag.Value.
+//
+// It panics if the struct cannot be used.
+func FromStruct(c Command, newRun func() TaggedRun) *Command {
+	v := newRun()
+	fields, err := parseStructFields(reflect.TypeOf(v))
+	if err != nil {
+		panic(fmt.Sprintf("subcommands: FromStruct(%q): %s", c.Name(), err))
+	}
+	// Register the flags once so invalid field types are caught early.
+	fs := &flag.FlagSet{}
+	if err := registerStructFlags(fs, reflect.ValueOf(v).Elem(), fields); err != nil {
+		panic(fmt.Sprintf("subcommands: FromStruct(%q): %s", c.Name(), err))
+	}
+	if err := registerOwnFlags(fs, v); err != nil {
+		panic(fmt.Sprintf("subcommands: FromStruct(%q): %s", c.Name(), err))
+	}
+	c.EnvVars = maps.Clone(c.EnvVars)
+	var args []Arg
+	for _, f := range fields {
+		if f.arg != nil {
+			args = append(args, *f.arg)
+			continue
+		}
+		if f.required {
+			c.RequiredFlags = append(c.RequiredFlags, f.flag)
+		}
+		if f.advanced {
+			c.AdvancedFlags = append(c.AdvancedFlags, f.flag)
+		}
+		if f.env != "" {
+			if c.EnvVars == nil {
+				c.EnvVars = map[string]EnvVarDefinition{}
+			}
+			c.EnvVars[f.env] = EnvVarDefinition{Advanced: f.advanced, ShortDesc: "Default value of -" + f.flag + "."}
+		}
+	}
+	if args != nil {
+		c.Args = args
+	}
+	c.CommandRun = func() CommandRun {
+		r := &structRun{v: newRun(), fields: fields}
+		rv := reflect.ValueOf(r.v).Elem()
+		// Errors were caught by FromStruct already.
+		_ = registerStructFlags(&r.Flags, rv, fields)
+		_ = registerOwnFlags(&r.Flags, r.v)
+		r.envErr = applyStructEnv(&r.Flags, fields)
+		return r
+	}
+	return &c
+}
+
+// structField is a tagged field of a struct used with FromStruct.
+type structField struct {
+	index    []int
+	flag     string
+	usage    string
+	env      string
+	required bool
+	advanced bool
+	arg      *Arg
+}
+
+// parseStructFields returns the tagged fields of t, which must be a pointer to
+// a struct.
+func parseStructFields(t reflect.Type) ([]structField, error) {
+	if t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a pointer to a struct, got %s", t)
+	}
+	var out []structField
+	for _, sf := range reflect.VisibleFields(t.Elem()) {
+		name, isFlag := sf.Tag.Lookup("flag")
+		argName, isArg := sf.Tag.Lookup("arg")
+		if !isFlag && !isArg {
+			continue
+		}
+		if !sf.IsExported() {
+			return nil, fmt.Errorf("field %s must be exported", sf.Name)
+		}
+		if isFlag && isArg {
+			return nil, fmt.Errorf("field %s can't be both a flag and an argument", sf.Name)
+		}
+		f := structField{
+			index:    sf.Index,
+			usage:    sf.Tag.Get("usage"),
+			env:      sf.Tag.Get("env"),
+			required: sf.Tag.Get("required") == "true",
+			advanced: sf.Tag.Get("advanced") == "true",
+		}
+		if isFlag {
+			if name == "" {
+				return nil, fmt.Errorf("field %s has an empty flag name", sf.Name)
+			}
+			f.flag = name
+			if f.env != "" {
+				f.usage += " ($" + f.env + ")"
+			}
+		} else {
+			if argName == "" {
+				return nil, fmt.Errorf("field %s has an empty argument name", sf.Name)
+			}
+			a := &Arg{Name: argName, Desc: f.usage, Optional: sf.Tag.Get("optional") == "true"}
+			ft := sf.Type
+			if ft.Kind() == reflect.Slice && !reflect.PointerTo(ft).Implements(flagValueType) {
+				a.Variadic = true
+				ft = ft.Elem()
+			}
+			t, err := argTypeOf(ft)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", sf.Name, err)
+			}
+			a.Type = t
+			f.arg = a
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+var (
+	flagValueType = reflect.TypeOf((*flag.Value)(nil)).Elem()
+	durationType  = reflect.TypeOf(time.Duration(0))
+)
+
+// argTypeOf returns the ArgType used to validate a positional argument stored
+// in a value of type t.
+func argTypeOf(t reflect.Type) (ArgType, error) {
+	if reflect.PointerTo(t).Implements(flagValueType) {
+		return ArgString, nil
+	}
+	if t == durationType {
+		return ArgDuration, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return ArgString, nil
+	case reflect.Bool:
+		return ArgBool, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ArgInt, nil
+	case reflect.Float32, reflect.Float64:
+		return ArgFloat, nil
+	default:
+		return 0, fmt.Errorf("unsupported argument type %s", t)
+	}
+}
+
+// registerStructFlags registers the flag fields of v into f.
+func registerStructFlags(f *flag.FlagSet, v reflect.Value, fields []structField) error {
+	for _, sf := range fields {
+		if sf.flag == "" {
+			continue
+		}
+		switch p := v.FieldByIndex(sf.index).Addr().Interface().(type) {
+		case flag.Value:
+			f.Var(p, sf.flag, sf.usage)
+		case *string:
+			f.StringVar(p, sf.flag, *p, sf.usage)
+		case *bool:
+			f.BoolVar(p, sf.flag, *p, sf.usage)
+		case *int:
+			f.IntVar(p, sf.flag, *p, sf.usage)
+		case *int64:
+			f.Int64Var(p, sf.flag, *p, sf.usage)
+		case *uint:
+			f.UintVar(p, sf.flag, *p, sf.usage)
+		case *uint64:
+			f.Uint64Var(p, sf.flag, *p, sf.usage)
+		case *float64:
+			f.Float64Var(p, sf.flag, *p, sf.usage)
+		case *time.Duration:
+			f.DurationVar(p, sf.flag, *p, sf.usage)
+		case *[]string:
+			v := &Strings{Value: *p}
+			f.Var(&fieldValue{v, func() { *p = v.Value }}, sf.flag, sf.usage)
+		case *[]int:
+			v := &Ints{Value: *p}
+			f.Var(&fieldValue{v, func() { *p = v.Value }}, sf.flag, sf.usage)
+		case *map[string]string:
+			v := &KeyValues{Value: *p}
+			f.Var(&fieldValue{v, func() { *p = v.Value }}, sf.flag, sf.usage)
+		default:
+			return fmt.Errorf("unsupported flag type %T for -%s", p, sf.flag)
+		}
+	}
+	return nil
+}
+
+// registerOwnFlags registers into f the flags of v's own FlagSet, if v has a
+// GetFlags method, e.g. by embedding CommandRunBase.
+func registerOwnFlags(f *flag.FlagSet, v TaggedRun) error {
+	g, ok := v.(interface{ GetFlags() *flag.FlagSet })
+	if !ok || g.GetFlags() == nil {
+		return nil
+	}
+	var err error
+	g.GetFlags().VisitAll(func(fl *flag.Flag) {
+		if err != nil {
+			return
+		}
+		if f.Lookup(fl.Name) != nil {
+			err = fmt.Errorf("flag -%s is defined twice", fl.Name)
+			return
+		}
+		f.Var(fl.Value, fl.Name, fl.Usage)
+	})
+	return err
+}
+
+// fieldValue is a flag.Value updating a struct field after each Set, for the
+// field types that have a flag.Value in this package, like []string. It
+// forwards the optional interfaces of the wrapped value, like Completer.
+type fieldValue struct {
+	flag.Value
+	update func()
+}
+
+func (f *fieldValue) String() string {
+	if f.Value == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+func (f *fieldValue) Set(s string) error {
+	if err := f.Value.Set(s); err != nil {
+		return err
+	}
+	f.update()
+	return nil
+}
+
+// Complete implements Completer if the wrapped value does.
+func (f *fieldValue) Complete(prefix string) []string {
+	if c, ok := f.Value.(Completer); ok {
+		return c.Complete(prefix)
+	}
+	return nil
+}
+
+// IsBoolFlag forwards to the wrapped value.
+func (f *fieldValue) IsBoolFlag() bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func (f *fieldValue) typeName() string {
+	if t, ok := f.Value.(valueTyper); ok {
+		return t.typeName()
+	}
+	return "value"
+}
+
+// applyStructEnv sets the flags from their environment variable, if set.
+//
+// The flags are set before the command line is parsed, so the command line
+// has precedence and the flag is considered specified for RequiredFlags.
+func applyStructEnv(f *flag.FlagSet, fields []structField) error {
+	for _, sf := range fields {
+		if sf.flag == "" || sf.env == "" {
+			continue
+		}
+		if val, ok := os.LookupEnv(sf.env); ok {
+			if err := f.Set(sf.flag, val); err != nil {
+				return fmt.Errorf("invalid value %q for environment variable %s: %w", val, sf.env, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setFromString parses s into v.
+func setFromString(v reflect.Value, s string) error {
+	if fv, ok := v.Addr().Interface().(flag.Value); ok {
+		return fv.Set(s)
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		e := reflect.New(v.Type().Elem()).Elem()
+		if err := setFromString(e, s); err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, e))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// structRun is the CommandRun created by FromStruct.
+type structRun struct {
+	CommandRunBase
+	v      TaggedRun
+	fields []structField
+	envErr error
+}
+
+func (s *structRun) Run(a Application, args []string, env Env) int {
+	if s.envErr != nil {
+		fmt.Fprintf(a.GetErr(), "%s %s\n", errorPrefix(a), s.envErr)
+		return 2
+	}
+	var specs []Arg
+	var values []reflect.Value
+	rv := reflect.ValueOf(s.v).Elem()
+	for _, f := range s.fields {
+		if f.arg != nil {
+			specs = append(specs, *f.arg)
+			values = append(values, rv.FieldByIndex(f.index))
+		}
+	}
+	if len(specs) != 0 {
+		// The arity was already validated by Run.
+		assigned, err := assignArgs(specs, args)
+		if err != nil {
+			fmt.Fprintf(a.GetErr(), "%s %s\n", errorPrefix(a), err)
+			return 2
+		}
+		for i, arg := range args {
+			if err := setFromString(values[assigned[i]], arg); err != nil {
+				fmt.Fprintf(a.GetErr(), "%s invalid value %q for argument %s: %s\n", errorPrefix(a), arg, specs[assigned[i]].label(), err)
+				return 2
+			}
+		}
+	}
+	return s.v.Run(a, args, env)
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/maruel/ut"
+)
+
+type copyRun struct {
+	Force   bool          `flag:"f" usage:"Overwrite"`
+	Mode    string        `flag:"mode" usage:"File mode" env:"TEST_COPY_MODE" required:"true"`
+	Retries int           `flag:"retries" usage:"Retries" advanced:"true"`
+	Timeout time.Duration `flag:"timeout" usage:"Timeout"`
+	Tags    []string      `flag:"tag" usage:"Tags"`
+	Count   int           `arg:"count"`
+	Dst     string        `arg:"dst" usage:"Destination"`
+	Srcs    []string      `arg:"src" usage:"Source files" optional:"true"`
+}
+
+func (c *copyRun) Run(a Application, args []string, env Env) int {
+	fmt.Fprintf(a.GetOut(), "%t %s %d %s %q %q %s %d\n", c.Force, c.Mode, c.Retries, c.Timeout, c.Tags, c.Srcs, c.Dst, c.Count)
+	return 0
+}
+
+func TestFromStruct(t *testing.T) {
+	cmd := FromStruct(Command{UsageLine: "cp <count> <dst> [<src>...]"}, func() TaggedRun {
+		return &copyRun{Mode: "0644", Timeout: time.Second, Tags: []string{"tmp"}}
+	})
+	ut.AssertEqual(t, []string{"mode"}, cmd.RequiredFlags)
+	ut.AssertEqual(t, []string{"retries"}, cmd.AdvancedFlags)
+	ut.AssertEqual(t, "cp", cmd.Name())
+	ut.AssertEqual(t, map[string]EnvVarDefinition{"TEST_COPY_MODE": {ShortDesc: "Default value of -mode."}}, cmd.EnvVars)
+	data := []struct {
+		args []string
+		env  string
+		out  string
+		err  string
+		exit int
+	}{
+		{
+			[]string{"cp", "-mode", "0600", "1", "b"},
+			"",
+			"false 0600 0 1s [\"tmp\"] [] b 1\n",
+			"",
+			0,
+		},
+		{
+			[]string{"cp", "-f", "-tag", "x", "-tag", "y", "3", "c", "a", "b"},
+			"0755",
+			"true 0755 0 1s [\"x\" \"y\"] [\"a\" \"b\"] c 3\n",
+			"",
+			0,
+		},
+		{
+			[]string{"cp", "-mode", "0600", "x", "b"},
+			"",
+			"",
+			"App: invalid value \"x\" for argument <count>: not a valid int\n\nRun 'App help cp' for usage.\n",
+			2,
+		},
+		{
+			[]string{"cp", "1", "b"},
+			"",
+			"",
+			"App: flag -mode is required\n\nRun 'App help cp' for usage.\n",
+			2,
+		},
+		{
+			[]string{"help", "cp"},
+			"",
+			"",
+			"usage:  App cp <count> <dst> [<src>...]\n" +
+				"  <count> int\n" +
+				"  <dst>\n    \tDestination\n" +
+				"  [<src>...]\n    \tSource files\n" +
+				"  -f\tOverwrite\n" +
+				"  -mode string\n    \tFile mode ($TEST_COPY_MODE) (default \"0644\") (required)\n" +
+				"  -tag string\n    \tTags (default tmp)\n" +
+				"  -timeout duration\n    \tTimeout (default 1s)\n" +
+				"\n" +
+				"Use \"App help -advanced cp\" to display all flags.\n",
+			0,
+		},
+	}
+	for i, line := range data {
+		line := line
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if line.env != "" {
+				t.Setenv("TEST_COPY_MODE", line.env)
+			}
+			a := application{
+				DefaultApplication: DefaultApplication{
+					Name:     "App",
+					Commands: []*Command{CmdHelp, cmd},
+				},
+			}
+			ut.AssertEqual(t, line.exit, Run(&a, line.args))
+			ut.AssertEqual(t, line.out, a.out.String())
+			ut.AssertEqual(t, line.err, a.err.String())
+		})
+	}
+}
+
+type badRun struct {
+	Chan chan int `flag:"chan"`
+}
+
+func (b *badRun) Run(a Application, args []string, env Env) int {
+	return 0
+}
+
+func TestFromStruct_Panic(t *testing.T) {
+	defer func() {
+		ut.AssertEqual(t, "subcommands: FromStruct(\"bad\"): unsupported flag type *chan int for -chan", recover())
+	}()
+	FromStruct(Command{UsageLine: "bad"}, func() TaggedRun { return &badRun{} })
+	t.Fatal("expected panic")
+}
+
+// embedRun defines a flag in its own FlagSet.
+type embedRun struct {
+	CommandRunBase
+	Name    string `flag:"name"`
+	verbose bool
+}
+
+func (e *embedRun) Run(a Application, args []string, env Env) int {
+	fmt.Fprintf(a.GetOut(), "%s %t\n", e.Name, e.verbose)
+	return 0
+}
+
+func TestFromStruct_OwnFlags(t *testing.T) {
+	cmd := FromStruct(Command{UsageLine: "embed"}, func() TaggedRun {
+		e := &embedRun{}
+		e.Flags.BoolVar(&e.verbose, "v", false, "verbose")
+		return e
+	})
+	a := &application{DefaultApplication: DefaultApplication{Name: "App", Commands: []*Command{cmd}}}
+	ut.AssertEqual(t, 0, Run(a, []string{"embed", "-v", "-name", "x"}))
+	ut.AssertEqual(t, "x true\n", a.out.String())
+
+	defer func() {
+		ut.AssertEqual(t, "subcommands: FromStruct(\"dup\"): flag -name is defined twice", recover())
+	}()
+	FromStruct(Command{UsageLine: "dup"}, func() TaggedRun {
+		e := &embedRun{}
+		e.Flags.String("name", "", "")
+		return e
+	})
+	t.Fatal("expected panic")
+}
+
+func TestSetFromString(t *testing.T) {
+	d := time.Second
+	ut.AssertEqual(t, true, setFromString(reflect.ValueOf(&d).Elem(), "bad") != nil)
+	ut.AssertEqual(t, time.Second, d)
+	ut.AssertEqual(t, nil, setFromString(reflect.ValueOf(&d).Elem(), "2m"))
+	ut.AssertEqual(t, 2*time.Minute, d)
+}
+
+func TestFieldValue(t *testing.T) {
+	labels := map[string]string{}
+	kv := &KeyValues{Value: labels, Keys: []string{"os"}}
+	f := &fieldValue{kv, func() { labels = kv.Value }}
+	ut.AssertEqual(t, []string{"os="}, f.Complete("o"))
+	ut.AssertEqual(t, false, f.IsBoolFlag())
+	ut.AssertEqual(t, "key=value", f.typeName())
+	ut.AssertEqual(t, nil, f.Set("os=linux"))
+	ut.AssertEqual(t, map[string]string{"os": "linux"}, labels)
+
+	var c Counter
+	f = &fieldValue{&c, func() {}}
+	ut.AssertEqual(t, true, f.IsBoolFlag())
+	ut.AssertEqual(t, []string(nil), f.Complete(""))
+}
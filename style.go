@@ -0,0 +1,126 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// Style describes how help pages and errors are styled on a terminal.
+//
+// Each field is the parameter of an ANSI SGR escape sequence, e.g. "1" for
+// bold or "1;31" for bold red. An empty field leaves the text unstyled.
+//
+// Styling is disabled when the output is not a terminal or when the NO_COLOR
+// environment variable is set to a non-empty value. It can be forced on a
+// non-terminal output by setting CLICOLOR_FORCE to a non-empty value.
+type Style struct {
+	// Heading is used for the section headings, including the ones created by
+	// Section.
+	Heading string
+	// Command is used for command names.
+	Command string
+	// Flag is used for flag names.
+	Flag string
+	// Default is used for the flags' default values.
+	Default string
+	// Error is used for the prefix of error messages.
+	Error string
+}
+
+// DefaultStyle returns the Style used when the application doesn't specify
+// one.
+func DefaultStyle() *Style {
+	return &Style{
+		Heading: "1",
+		Command: "36",
+		Flag:    "33",
+		Default: "2",
+		Error:   "1;31",
+	}
+}
+
+// StyleKind is the kind of text styled by Style.Apply.
+//
+// It is a string so the templates of TemplateRenderer can specify it as a
+// literal, e.g. {{style "heading" "Usage:"}}.
+type StyleKind string
+
+const (
+	// StyleHeading is a section heading. See Style.Heading.
+	StyleHeading StyleKind = "heading"
+	// StyleCommand is a command name. See Style.Command.
+	StyleCommand StyleKind = "command"
+	// StyleFlag is a flag name. See Style.Flag.
+	StyleFlag StyleKind = "flag"
+	// StyleDefault is the default value of a flag. See Style.Default.
+	StyleDefault StyleKind = "default"
+	// StyleError is the prefix of an error message. See Style.Error.
+	StyleError StyleKind = "error"
+)
+
+// StyledApplication is implemented by an Application that specifies the Style
+// of its help pages and errors. GetStyle can return nil to disable styling.
+//
+// Applications that do not implement it use DefaultStyle.
+type StyledApplication interface {
+	Application
+	GetStyle() *Style
+}
+
+// Apply returns text styled as kind. Leading and trailing whitespace is left
+// unstyled so alignment and line breaks are preserved. Unknown kinds are left
+// unstyled.
+//
+// It is safe to call on a nil Style, in which case s is returned as is.
+func (s *Style) Apply(kind StyleKind, text string) string {
+	if s == nil {
+		return text
+	}
+	var code string
+	switch kind {
+	case StyleHeading:
+		code = s.Heading
+	case StyleCommand:
+		code = s.Command
+	case StyleFlag:
+		code = s.Flag
+	case StyleDefault:
+		code = s.Default
+	case StyleError:
+		code = s.Error
+	}
+	trimmed := strings.TrimSpace(text)
+	if code == "" || trimmed == "" {
+		return text
+	}
+	i := strings.Index(text, trimmed)
+	return text[:i] + "\x1b[" + code + "m" + trimmed + "\x1b[0m" + text[i+len(trimmed):]
+}
+
+// styleFor returns the Style to use for the output of a written to w, or nil
+// if the output shouldn't be styled.
+func styleFor(a Application, w io.Writer) *Style {
+	if v := os.Getenv("NO_COLOR"); v != "" {
+		return nil
+	}
+	if os.Getenv("CLICOLOR_FORCE") == "" {
+		f, ok := w.(*os.File)
+		if !ok || !isTerminal(f) {
+			return nil
+		}
+	}
+	if s, ok := AppAs[StyledApplication](a); ok {
+		return s.GetStyle()
+	}
+	return DefaultStyle()
+}
+
+// errorPrefix returns the prefix of error messages printed to a.GetErr().
+func errorPrefix(a Application) string {
+	return styleFor(a, a.GetErr()).Apply(StyleError, a.GetName()+":")
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func TestStyle_Apply(t *testing.T) {
+	var nilStyle *Style
+	ut.AssertEqual(t, "foo", nilStyle.Apply(StyleHeading, "foo"))
+	ut.AssertEqual(t, "\x1b[1mfoo\x1b[0m", DefaultStyle().Apply(StyleHeading, "foo"))
+	ut.AssertEqual(t, "\n\t\x1b[1mfoo bar\x1b[0m ", DefaultStyle().Apply(StyleHeading, "\n\tfoo bar "))
+	ut.AssertEqual(t, "", DefaultStyle().Apply(StyleCommand, ""))
+	ut.AssertEqual(t, "foo", DefaultStyle().Apply("unknown", "foo"))
+	ut.AssertEqual(t, "foo", (&Style{}).Apply(StyleError, "foo"))
+}
+
+func TestStyleFor(t *testing.T) {
+	a := &DefaultApplication{Name: "App"}
+	buf := bytes.Buffer{}
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	ut.AssertEqual(t, (*Style)(nil), styleFor(a, &buf))
+	t.Setenv("CLICOLOR_FORCE", "1")
+	ut.AssertEqual(t, DefaultStyle(), styleFor(a, &buf))
+	a.Style = &Style{Error: "31"}
+	ut.AssertEqual(t, a.Style, styleFor(a, &buf))
+	t.Setenv("NO_COLOR", "1")
+	ut.AssertEqual(t, (*Style)(nil), styleFor(a, &buf))
+}
+
+func TestUsage_Style(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "1")
+	a := &DefaultApplication{
+		Name:  "App",
+		Title: "Title",
+		Style: &Style{Heading: "1", Command: "36"},
+		Commands: []*Command{
+			{UsageLine: "foo", ShortDesc: "foo desc"},
+			Section("Bar"),
+			{UsageLine: "barbaz", ShortDesc: "barbaz desc"},
+		},
+	}
+	buf := bytes.Buffer{}
+	Usage(&buf, a, false)
+	ut.AssertEqual(t, "Title\n\n"+
+		"\x1b[1mUsage:\x1b[0m  App [command] [arguments]\n\n"+
+		"\x1b[1mCommands:\x1b[0m\n"+
+		"  \x1b[36mfoo\x1b[0m     foo desc\n"+
+		"          \n\t\x1b[1mBar\x1b[0m\n"+
+		"  \x1b[36mbarbaz\x1b[0m  barbaz desc\n\n\n"+
+		"Use \"App help [command]\" for more information about a command.\n\n", buf.String())
+}
+
+func TestPrintDefaults_Style(t *testing.T) {
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	f.Bool("v", false, "verbose")
+	f.String("name", "bob", "the name")
+	buf := bytes.Buffer{}
+	printDefaults(&buf, &Style{Flag: "33", Default: "2"}, &Command{}, f, false)
+	ut.AssertEqual(t, "  \x1b[33m-name\x1b[0m string\n"+
+		"    \tthe name (default \x1b[2m\"bob\"\x1b[0m)\n"+
+		"  \x1b[33m-v\x1b[0m\tverbose\n", buf.String())
+}
+
+func TestRun_StyleError(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "1")
+	a := &application{DefaultApplication: DefaultApplication{Name: "App"}}
+	ut.AssertEqual(t, 2, Run(a, []string{"inexistant"}))
+	ut.AssertEqual(t, "\x1b[1;31mApp:\x1b[0m unknown command `inexistant`\n\nRun 'App help' for usage.\n", a.err.String())
+}
+
+func TestRun_StyleWarning(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "1")
+	a := &application{
+		DefaultApplication: DefaultApplication{
+			Name: "App",
+			Commands: []*Command{
+				{
+					UsageLine:       "cmd",
+					DeprecatedFlags: map[string]string{"old": ""},
+					CommandRun: func() CommandRun {
+						c := &command{}
+						c.Flags.Bool("old", false, "Old")
+						return c
+					},
+				},
+			},
+		},
+	}
+	ut.AssertEqual(t, 42, Run(a, []string{"cmd", "-old"}))
+	ut.AssertEqual(t, "\x1b[1;31mApp:\x1b[0m flag -old is deprecated\n", a.err.String())
+}
@@ -14,11 +14,13 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
-	"text/template"
+	"sync/atomic"
 
 	"github.com/texttheater/golang-levenshtein/levenshtein"
 )
@@ -63,6 +65,25 @@ type DefaultApplication struct {
 	Title    string
 	Commands []*Command
 	EnvVars  map[string]EnvVarDefinition
+	// Middlewares wrap the execution of every command. See Middleware.
+	Middlewares []Middleware
+	// Style is the style of help pages and errors. DefaultStyle is used when
+	// nil. Set it to &Style{} to disable styling.
+	Style *Style
+	// HelpRenderer renders the help pages. The default templates are used when
+	// nil.
+	HelpRenderer HelpRenderer
+	// HelpTopics are help pages that are not commands. See HelpTopic.
+	HelpTopics []*HelpTopic
+	// MatchPolicy controls how the command to run is found from the name typed
+	// on the command line. It defaults to MatchFuzzy.
+	MatchPolicy MatchPolicy
+	// Matcher finds the command the user meant when the name typed is not
+	// exact. DefaultMatcher is used when nil.
+	Matcher Matcher
+	// EnvVarCheck enables warnings about undeclared environment variables
+	// similar to the declared ones. It is disabled when nil.
+	EnvVarCheck *EnvVarCheck
 }
 
 // GetName implements interface Application.
@@ -95,6 +116,39 @@ func (a *DefaultApplication) GetEnvVars() map[string]EnvVarDefinition {
 	return a.EnvVars
 }
 
+// GetStyle implements interface StyledApplication.
+func (a *DefaultApplication) GetStyle() *Style {
+	if a.Style == nil {
+		return DefaultStyle()
+	}
+	return a.Style
+}
+
+// GetHelpRenderer implements interface HelpRendererApplication.
+func (a *DefaultApplication) GetHelpRenderer() HelpRenderer {
+	return a.HelpRenderer
+}
+
+// GetHelpTopics implements interface HelpTopicsApplication.
+func (a *DefaultApplication) GetHelpTopics() []*HelpTopic {
+	return a.HelpTopics
+}
+
+// GetMatchPolicy implements interface MatchPolicyApplication.
+func (a *DefaultApplication) GetMatchPolicy() MatchPolicy {
+	return a.MatchPolicy
+}
+
+// GetMatcher implements interface MatcherApplication.
+func (a *DefaultApplication) GetMatcher() Matcher {
+	return a.Matcher
+}
+
+// GetEnvVarCheck implements interface EnvVarCheckApplication.
+func (a *DefaultApplication) GetEnvVarCheck() *EnvVarCheck {
+	return a.EnvVarCheck
+}
+
 // Env is the mapping of resolved environment variables passed to
 // CommandRun.Run.
 type Env map[string]EnvVar
@@ -145,7 +199,123 @@ type Command struct {
 	Advanced   bool
 	CommandRun func() CommandRun
 
+	// ExactMatch requires the command to be typed in full to be run, whatever
+	// the application's MatchPolicy. It is useful for destructive commands.
+	ExactMatch bool
+	// Aliases are alternative names for the command, e.g. "rm" for "remove".
+	// An alias typed in full runs the command like its name does.
+	Aliases []string
+
+	// Args declares the positional arguments accepted by the command. When set,
+	// Run validates the arguments before calling CommandRun.Run and they are
+	// listed in the command's help.
+	//
+	// When nil, the arguments are derived from UsageLine with ParseUsageLine.
+	Args []Arg
+	// FreeForm disables the validation of positional arguments derived from
+	// UsageLine. It is useful for commands that do their own argument parsing,
+	// for example commands forwarding their arguments to another application.
+	FreeForm bool
+
+	// RequiredFlags lists the flags, by name, that must be specified on the
+	// command line. It is enforced by Run after the flags are parsed.
+	//
+	// The flags referenced by RequiredFlags, FlagGroups, AdvancedFlags and
+	// DeprecatedFlags must be defined, otherwise Run panics.
+	RequiredFlags []string
+	// FlagGroups lists constraints on groups of flags. They are enforced by Run
+	// after the flags are parsed.
+	FlagGroups []FlagGroup
+	// AdvancedFlags lists the flags, by name, that are only shown with
+	// "help -advanced <command>".
+	AdvancedFlags []string
+	// DeprecatedFlags maps the name of deprecated flags to the name of the flag
+	// replacing it, which can be empty. Deprecated flags are hidden from help
+	// and a warning is printed when they are used.
+	DeprecatedFlags map[string]string
+
+	// Middlewares wrap the execution of this command, inside the application's
+	// middlewares. See Middleware.
+	Middlewares []Middleware
+
+	// Examples are listed in the command's help and in the generated
+	// documentation. They can be verified with subcommandstest.CheckExamples.
+	Examples []Example
+
+	// EnvVars are the environment variables used by this command, in addition
+	// to the application's. They are listed with the application's in help
+	// pages and docs, and resolved in the Env passed to CommandRun.Run. The
+	// application's definition has precedence for a name defined by both.
+	EnvVars map[string]EnvVarDefinition
+
 	isSection bool
+	// loader, if set, completes the command's description when it is first
+	// needed. See loadCommands.
+	loader *commandLoader
+	// appCheck, if set, returns an error if the command can't be run by the
+	// application. See AppCommand.
+	appCheck func(a Application) error
+}
+
+// commandLoader completes the description of a Command, e.g. a plugin that
+// has to be run to describe itself.
+//
+// The Command it is attached to is never modified, so its name and aliases can
+// be read at any time. The complete description is a separate Command returned
+// by loadCommands.
+type commandLoader struct {
+	once sync.Once
+	load func() *Command
+	// cached, if set, returns the complete description if it is available
+	// without loading it, or nil.
+	cached func() *Command
+	loaded atomic.Pointer[Command]
+}
+
+// get returns the complete description, loading it on first use.
+func (l *commandLoader) get() *Command {
+	l.once.Do(func() { l.loaded.Store(l.load()) })
+	return l.loaded.Load()
+}
+
+// peek returns the complete description if it was already loaded or is
+// cached, or nil.
+func (l *commandLoader) peek() *Command {
+	if c := l.loaded.Load(); c != nil {
+		return c
+	}
+	if l.cached != nil {
+		return l.cached()
+	}
+	return nil
+}
+
+// loadCommands returns cmds with the commands having a loader replaced by
+// their complete description, loaded concurrently. It must be used to read
+// the description beyond the command's name and aliases, e.g. for help, docs,
+// completion or to run it. cmds is not modified.
+func loadCommands(cmds ...*Command) []*Command {
+	out := slices.Clone(cmds)
+	wg := sync.WaitGroup{}
+	for i, c := range cmds {
+		if c.loader != nil {
+			wg.Add(1)
+			go func(i int, l *commandLoader) {
+				defer wg.Done()
+				out[i] = l.get()
+			}(i, c.loader)
+		}
+	}
+	wg.Wait()
+	return out
+}
+
+// described returns the complete description of c. See loadCommands.
+func (c *Command) described() *Command {
+	if c.loader == nil {
+		return c
+	}
+	return c.loader.get()
 }
 
 // Name returns the command's name: the first word in the usage line.
@@ -158,6 +328,28 @@ func (c *Command) Name() string {
 	return name
 }
 
+// IsSection returns true if the command was created by Section.
+func (c *Command) IsSection() bool {
+	return c.isSection
+}
+
+// args returns the positional arguments accepted by the command and whether
+// they should be validated.
+func (c *Command) args() ([]Arg, bool) {
+	if c.Args != nil {
+		return c.Args, true
+	}
+	if c.FreeForm {
+		return nil, false
+	}
+	specs, err := ParseUsageLine(c.UsageLine)
+	if err != nil {
+		// Do not fail on a usage line written in free form.
+		return nil, false
+	}
+	return specs, true
+}
+
 // Section returns an un-runnable command that can act as a nice section
 // heading for other commands.
 func Section(name string) *Command {
@@ -175,97 +367,108 @@ func Section(name string) *Command {
 // Beware that using the form "<tool> help -advanced <command>" will not
 // propagate CmdHelp's help into the subcommand help Usage.
 func Usage(out io.Writer, a Application, includeAdvanced bool) {
-	usageTemplate := `{{.Title}}
-
-Usage:  {{.Name}} [command] [arguments]
-
-Commands:{{range .Commands}}
-  {{.Name | printf "%%-%ds"}}  {{.ShortDesc}}{{end}}
-
-{{if .EnvVars}}Environment Variables:{{range .EnvVars}}
-  {{.Name | printf "%%-%ds"}}  {{.ShortDesc}}{{if .Default}} (Default: {{.Default | printf "%%q"}}){{end}}{{end}}
-
-{{end}}
-Use "{{.Name}} help [command]" for more information about a command.{{if .ShowAdvancedTip}}
-Use "{{.Name}} help -advanced" to display all commands.{{end}}
-
-`
-
 	widestCmd := 0
 	allCmds := a.GetCommands()
 	cmds := make([]*Command, 0, len(allCmds))
 	hasAdvanced := false
 	for _, c := range allCmds {
+		if c.loader != nil {
+			// Listing the commands must be fast. Do not load the descriptions.
+			if d := c.loader.peek(); d != nil {
+				c = d
+			}
+		}
 		hasAdvanced = hasAdvanced || c.Advanced
 
 		if !c.Advanced || includeAdvanced {
 			// We need to include this command
-			if namLen := len(c.Name()); namLen > widestCmd {
+			if namLen := displayWidth(c.Name()); namLen > widestCmd {
 				widestCmd = namLen
 			}
 			cmds = append(cmds, c)
 		}
 	}
 
-	type envVarEntry struct {
-		Name      string
-		ShortDesc string
-		Default   string
+	widestTopic := 0
+	topics := getHelpTopics(a)
+	for _, t := range topics {
+		if w := displayWidth(t.Name); w > widestTopic {
+			widestTopic = w
+		}
 	}
+
 	widestEnvVar := 0
-	envVars := []envVarEntry(nil)
-	if envVarMap := a.GetEnvVars(); len(envVarMap) > 0 {
+	envVars := []UsageEnvVar(nil)
+	if envVarMap := allEnvVars(a); len(envVarMap) > 0 {
 		envVarKeys := make(sort.StringSlice, 0, len(envVarMap))
 		for k, v := range envVarMap {
 			if v.Advanced {
 				hasAdvanced = true
 			}
 			if !v.Advanced || includeAdvanced {
-				if keyLen := len(k); keyLen > widestEnvVar {
+				if keyLen := displayWidth(k); keyLen > widestEnvVar {
 					widestEnvVar = keyLen
 				}
 				envVarKeys = append(envVarKeys, k)
 			}
 		}
 		envVarKeys.Sort()
-		envVars = make([]envVarEntry, 0, len(envVarKeys))
+		envVars = make([]UsageEnvVar, 0, len(envVarKeys))
 		for _, k := range envVarKeys {
 			v := envVarMap[k]
-			envVars = append(envVars, envVarEntry{k, v.ShortDesc, v.Default})
+			envVars = append(envVars, UsageEnvVar{k, v.ShortDesc, v.Default})
 		}
 	}
-	data := map[string]interface{}{
-		"Title":           a.GetTitle(),
-		"Name":            a.GetName(),
-		"Commands":        cmds,
-		"EnvVars":         envVars,
-		"ShowAdvancedTip": (hasAdvanced && !includeAdvanced),
+	data := &UsageData{
+		Title:           a.GetTitle(),
+		Name:            a.GetName(),
+		Commands:        cmds,
+		HelpTopics:      topics,
+		EnvVars:         envVars,
+		ShowAdvancedTip: (hasAdvanced && !includeAdvanced),
+		WidestCmd:       widestCmd,
+		CmdIndent:       widestCmd + 4,
+		WidestTopic:     widestTopic,
+		TopicIndent:     widestTopic + 4,
+		WidestEnvVar:    widestEnvVar,
+		EnvVarIndent:    widestEnvVar + 4,
+		Style:           styleFor(a, out),
+	}
+	if err := helpRendererFor(a).RenderUsage(out, data); err != nil {
+		panic(fmt.Sprintf("Failed to execute template: %s", err))
 	}
-	tmpl(out, fmt.Sprintf(usageTemplate, widestCmd, widestEnvVar), data)
 }
 
 // getCommandUsageHandler returns a flag.Usage compatible function.
-func getCommandUsageHandler(out io.Writer, a Application, c *Command, r CommandRun, helpUsed *bool) func() {
+func getCommandUsageHandler(out io.Writer, a Application, c *Command, r CommandRun, helpUsed *bool, includeAdvanced bool) func() {
 	return func() {
-		helpTemplate := "{{.Cmd.LongDesc | trim | wrapWithLines}}usage:  {{.App.GetName}} {{.Cmd.UsageLine}}\n"
-		dict := struct {
-			App Application
-			Cmd *Command
-		}{a, c}
-		tmpl(out, helpTemplate, dict)
-		if f := r.GetFlags(); f != nil {
-			f.PrintDefaults()
+		specs, _ := c.args()
+		data := &CommandHelpData{
+			App:     a,
+			Cmd:     c,
+			Args:    specs,
+			FlagSet: r.GetFlags(),
+			Style:   styleFor(a, out),
+		}
+		if data.FlagSet != nil {
+			b := strings.Builder{}
+			data.ShowAdvancedTip = printDefaults(&b, data.Style, c, data.FlagSet, includeAdvanced)
+			data.Flags = b.String()
+		}
+		if err := helpRendererFor(a).RenderCommandHelp(out, data); err != nil {
+			panic(fmt.Sprintf("Failed to execute template: %s", err))
 		}
 		*helpUsed = true
 	}
 }
 
 // Initializes the flags for a specific CommandRun.
-func initCommand(a Application, c *Command, r CommandRun, out io.Writer, helpUsed *bool) (hasFlags bool) {
+func initCommand(a Application, c *Command, r CommandRun, out io.Writer, helpUsed *bool, includeAdvanced bool) (hasFlags bool) {
 	f := r.GetFlags()
+	checkFlagNames(c, f)
 	if f != nil {
 		if f.Usage == nil {
-			f.Usage = getCommandUsageHandler(out, a, c, r, helpUsed)
+			f.Usage = getCommandUsageHandler(out, a, c, r, helpUsed, includeAdvanced)
 		}
 		f.SetOutput(out)
 		f.Init(c.Name(), flag.ContinueOnError)
@@ -273,75 +476,125 @@ func initCommand(a Application, c *Command, r CommandRun, out io.Writer, helpUse
 	return f != nil
 }
 
-// FindCommand finds a Command by name and returns it if found.
+// FindCommand finds a Command by name or by one of its Aliases and returns it
+// if found. Names have precedence over aliases.
 func FindCommand(a Application, name string) *Command {
 	for _, c := range a.GetCommands() {
 		if c.Name() == name {
 			return c
 		}
 	}
-	return nil
+	return findAlias(runnableCommands(a), name)
 }
 
 // FindNearestCommand heuristically finds a Command the user wanted to type but
-// failed to type correctly.
+// failed to type correctly, using the application's Matcher.
 func FindNearestCommand(a Application, name string) *Command {
-	commands := map[string]*Command{}
+	if c := FindCommand(a, name); c != nil && !c.isSection {
+		return c
+	}
+	return getMatcher(a).Find(runnableCommands(a), name)
+}
+
+// allEnvVars returns the environment variables of a and of its commands. The
+// application's definition has precedence.
+func allEnvVars(a Application) map[string]EnvVarDefinition {
+	out := a.GetEnvVars()
+	cloned := false
 	for _, c := range a.GetCommands() {
-		if !c.isSection {
-			commands[c.Name()] = c
+		for k, v := range c.EnvVars {
+			if _, ok := out[k]; ok {
+				continue
+			}
+			if !cloned {
+				out = maps.Clone(out)
+				if out == nil {
+					out = map[string]EnvVarDefinition{}
+				}
+				cloned = true
+			}
+			out[k] = v
 		}
 	}
-	if c, ok := commands[name]; ok {
-		return c
+	return out
+}
+
+// runnableCommands returns the commands of a, skipping the sections.
+func runnableCommands(a Application) []*Command {
+	var out []*Command
+	for _, c := range a.GetCommands() {
+		if !c.isSection {
+			out = append(out, c)
+		}
 	}
+	return out
+}
 
-	// Search for unique prefix.
-	withPrefix := []*Command{}
-	for n, c := range commands {
-		if strings.HasPrefix(n, name) {
-			withPrefix = append(withPrefix, c)
+// findNearest heuristically finds the name the user wanted to type but failed
+// to type correctly and returns its index in names, or -1.
+//
+// When a name is present multiple times, the first one is used.
+func findNearest(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
 		}
 	}
-	if len(withPrefix) == 1 {
-		return withPrefix[0]
+
+	// Search for unique prefix.
+	if i := uniqueMatch(names, func(n string) bool { return strings.HasPrefix(n, name) }); i >= 0 {
+		return i
 	}
 
 	// Search for case insensitivity.
-	withPrefix = []*Command{}
 	lowName := strings.ToLower(name)
-	for n, c := range commands {
-		if strings.HasPrefix(strings.ToLower(n), lowName) {
-			withPrefix = append(withPrefix, c)
-		}
-	}
-	if len(withPrefix) == 1 {
-		return withPrefix[0]
+	if i := uniqueMatch(names, func(n string) bool { return strings.HasPrefix(strings.ToLower(n), lowName) }); i >= 0 {
+		return i
 	}
 
 	// Calculate the levenshtein distance and take the closest one.
 	closestD := 1000
-	var closestC *Command
+	closest := -1
 	secondD := 1000
-	for n, c := range commands {
+	for i, n := range names {
+		if slices.Contains(names[:i], n) {
+			continue
+		}
 		dist := levenshtein.DistanceForStrings([]rune(n), []rune(name), levenshtein.DefaultOptions)
 		if dist < closestD {
 			secondD = closestD
 			closestD = dist
-			closestC = c
+			closest = i
 		} else if dist < secondD {
 			secondD = dist
 		}
 	}
 	if closestD > 3 {
 		// Not similar enough. Don't be a fool and run a random command.
-		return nil
+		return -1
 	}
 	if (secondD - closestD) < 3 {
 		// Too ambiguous.
-		return nil
+		return -1
+	}
+	return closest
+}
+
+// uniqueMatch returns the index of the only distinct name matching, or -1.
+func uniqueMatch(names []string, match func(n string) bool) int {
+	found := -1
+	for i, n := range names {
+		if !match(n) {
+			continue
+		}
+		if found >= 0 && names[found] != n {
+			return -1
+		}
+		if found < 0 {
+			found = i
+		}
 	}
-	return closestC
+	return found
 }
 
 // Run runs the application, scheduling the subcommand. This is the main entry
@@ -354,11 +607,20 @@ func FindNearestCommand(a Application, name string) *Command {
 // embedded by each CommandRun implementation to define flags available for
 // all commands.
 func Run(a Application, args []string) int {
+	checkApplication(a)
 	// Process general flags first, mainly for -help.
 	helpUsed := false
 	if args == nil {
-		// Do not parse during unit tests because flag.commandLine.errorHandling == ExitOnError. :(
-		args, helpUsed = parseGeneral(a)
+		if len(os.Args) > 1 && isVersionFlag(a, os.Args[1]) {
+			// -version is not a registered general flag.
+			args = os.Args[1:]
+		} else {
+			// Do not parse during unit tests because flag.commandLine.errorHandling == ExitOnError. :(
+			args, helpUsed = parseGeneral(a)
+		}
+	}
+	if len(args) != 0 && isVersionFlag(a, args[0]) {
+		args = append([]string{"version"}, args[1:]...)
 	}
 
 	if len(args) < 1 {
@@ -367,23 +629,52 @@ func Run(a Application, args []string) int {
 		return 2
 	}
 
-	if c := FindNearestCommand(a, args[0]); c != nil {
+	if t := FindHelpTopic(a, args[0]); t != nil && FindCommand(a, args[0]) == nil {
+		// Help topics are never runnable, and must not run a similarly named
+		// command either.
+		fmt.Fprintf(a.GetErr(), "%s %#q is a help topic, not a command\n\nRun '%s help %s' to read it.\n", errorPrefix(a), t.Name, a.GetName(), t.Name)
+		return 2
+	}
+
+	c, suggestion := matchCommand(a, args[0])
+	if c != nil {
+		c = c.described()
 		// Initialize the flags.
 		r := c.CommandRun()
-		hasFlags := initCommand(a, c, r, a.GetErr(), &helpUsed)
+		hasFlags := initCommand(a, c, r, a.GetErr(), &helpUsed, false)
 		var cmdArgs []string
 		if hasFlags {
-			if err := r.GetFlags().Parse(args[1:]); err != nil {
+			if err := parseFlags(a, c, r.GetFlags(), args[1:]); err != nil {
 				return 2
 			}
 			if helpUsed {
 				return 0
 			}
+			if err := validateFlags(c, r.GetFlags()); err != nil {
+				return usageError(a, c, err)
+			}
+			warnDeprecatedFlags(a, c, r.GetFlags())
 			cmdArgs = r.GetFlags().Args()
 		} else {
 			cmdArgs = args[1:]
 		}
+		if specs, ok := c.args(); ok {
+			if err := validateArgs(specs, cmdArgs); err != nil {
+				return usageError(a, c, err)
+			}
+		}
 		envVars := a.GetEnvVars()
+		if len(c.EnvVars) != 0 {
+			envVars = maps.Clone(envVars)
+			if envVars == nil {
+				envVars = map[string]EnvVarDefinition{}
+			}
+			for k, v := range c.EnvVars {
+				if _, ok := envVars[k]; !ok {
+					envVars[k] = v
+				}
+			}
+		}
 		envMap := make(map[string]EnvVar, len(envVars))
 		for k, v := range envVars {
 			val, ok := os.LookupEnv(k)
@@ -392,10 +683,18 @@ func Run(a Application, args []string) int {
 			}
 			envMap[k] = EnvVar{val, ok}
 		}
-		return r.Run(a, cmdArgs, envMap)
+		warnUnknownEnvVars(a)
+		return runInvocation(&Invocation{App: a, Command: c, CommandRun: r, Args: cmdArgs, Env: envMap})
 	}
 
-	fmt.Fprintf(a.GetErr(), "%s: unknown command %#q\n\nRun '%s help' for usage.\n", a.GetName(), args[0], a.GetName())
+	printUnknownCommand(a, args[0], suggestion)
+	return 2
+}
+
+// usageError prints an error about the command line of c and returns the exit
+// code to use.
+func usageError(a Application, c *Command, err error) int {
+	fmt.Fprintf(a.GetErr(), "%s %s\n\nRun '%s help %s' for usage.\n", errorPrefix(a), err, a.GetName(), c.Name())
 	return 2
 }
 
@@ -421,16 +720,6 @@ func parseGeneral(a Application) ([]string, bool) {
 	return flag.Args(), helpUsed
 }
 
-// tmpl executes the given template text on data, writing the result to w.
-func tmpl(w io.Writer, text string, data interface{}) {
-	t := template.New("top")
-	t.Funcs(template.FuncMap{"trim": strings.TrimSpace, "wrapWithLines": wrapWithLines})
-	template.Must(t.Parse(text))
-	if err := t.Execute(w, data); err != nil {
-		panic(fmt.Sprintf("Failed to execute template: %s", err))
-	}
-}
-
 func wrapWithLines(s string) string {
 	if s == "" {
 		return s
@@ -443,12 +732,14 @@ func wrapWithLines(s string) string {
 //
 // It is not added automatically but it will be run automatically if added.
 var CmdHelp = &Command{
-	UsageLine: "help [<command>|-advanced]",
+	UsageLine: "help [-advanced] [<command>|<topic>|-search <keyword>...]",
 	ShortDesc: "prints help about a command",
-	LongDesc:  "Prints an overview of every command or information about a specific command.\nPass -advanced to see help for advanced commands.",
+	LongDesc:  "Prints an overview of every command or information about a specific command or help topic.\nPass -advanced to see help for advanced commands and flags.\nPass -search to list the commands, flags, environment variables and help topics matching keywords.",
+	FreeForm:  true,
 	CommandRun: func() CommandRun {
 		ret := &helpRun{}
-		ret.Flags.BoolVar(&ret.advanced, "advanced", false, "show advanced commands")
+		ret.Flags.BoolVar(&ret.advanced, "advanced", false, "show advanced commands and flags")
+		ret.Flags.BoolVar(&ret.search, "search", false, "search the commands, flags, environment variables and help topics matching the arguments")
 		return ret
 	},
 }
@@ -456,30 +747,47 @@ var CmdHelp = &Command{
 type helpRun struct {
 	CommandRunBase
 	advanced bool
+	search   bool
 }
 
 func (c *helpRun) Run(a Application, args []string, env Env) int {
+	if c.search {
+		if len(args) == 0 {
+			fmt.Fprintf(a.GetErr(), "%s -search requires keywords\n\nRun '%s help help' for usage.\n", errorPrefix(a), a.GetName())
+			return 2
+		}
+		printSearch(a.GetOut(), a, strings.Join(args, " "), c.advanced)
+		return 0
+	}
 	if len(args) == 0 {
 		Usage(a.GetOut(), a, c.advanced)
 		return 0
 	}
 	if len(args) != 1 {
-		fmt.Fprintf(a.GetErr(), "%s: Too many arguments given\n\nRun '%s help' for usage.\n", a.GetName(), a.GetName())
+		fmt.Fprintf(a.GetErr(), "%s Too many arguments given\n\nRun '%s help' for usage.\n", errorPrefix(a), a.GetName())
 		return 2
 	}
 	// Redirects all output to Out.
 	var helpUsed bool
-	if cmd := FindNearestCommand(a, args[0]); cmd != nil {
+	cmd, topic := findNearestHelp(a, args[0])
+	if topic != nil {
+		if err := printHelpTopic(a.GetOut(), a, topic); err != nil {
+			panic(fmt.Sprintf("Failed to execute template: %s", err))
+		}
+		return 0
+	}
+	if cmd != nil {
+		cmd = cmd.described()
 		// Initialize the flags.
 		r := cmd.CommandRun()
-		if initCommand(a, cmd, r, a.GetErr(), &helpUsed) {
+		if initCommand(a, cmd, r, a.GetErr(), &helpUsed, c.advanced) {
 			r.GetFlags().Usage()
 		} else {
-			getCommandUsageHandler(a.GetErr(), a, cmd, r, &helpUsed)()
+			getCommandUsageHandler(a.GetErr(), a, cmd, r, &helpUsed, c.advanced)()
 		}
 		return 0
 	}
 
-	fmt.Fprintf(a.GetErr(), "%s: unknown command %#q\n\nRun '%s help' for usage.\n", a.GetName(), args[0], a.GetName())
+	printUnknownCommand(a, args[0], nil)
 	return 2
 }
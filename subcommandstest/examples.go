@@ -0,0 +1,87 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommandstest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maruel/subcommands"
+)
+
+// CheckExamples runs every subcommands.Example of the commands of a, each in
+// its own subtest, and asserts that it exits with the expected exit code.
+//
+// Each example runs with an ApplicationMock wrapping a so the output is
+// captured. It is printed when the exit code doesn't match.
+func CheckExamples(t *testing.T, a subcommands.Application) {
+	for _, c := range a.GetCommands() {
+		for _, e := range c.Examples {
+			e := e
+			t.Run(e.Command, func(t *testing.T) {
+				args, err := SplitCommandLine(e.Command)
+				if err != nil {
+					t.Fatalf("invalid example %q: %s", e.Command, err)
+				}
+				m := MakeAppMock(t, a)
+				if got := subcommands.Run(m, args); got != e.ExitCode {
+					t.Errorf("%s %s: got exit code %d, expected %d\nstdout:\n%s\nstderr:\n%s", a.GetName(), e.Command, got, e.ExitCode, m.bufOut.String(), m.bufErr.String())
+				}
+			})
+		}
+	}
+}
+
+// SplitCommandLine splits a command line into arguments like a POSIX shell
+// does, without any expansion.
+//
+// Arguments can be quoted with single or double quotes. A backslash escapes
+// the next character, except within single quotes.
+func SplitCommandLine(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommandstest
+
+import (
+	"testing"
+
+	"github.com/maruel/subcommands"
+	"github.com/maruel/ut"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	data := []struct {
+		in       string
+		expected []string
+		err      bool
+	}{
+		{"", nil, false},
+		{"  a  b ", []string{"a", "b"}, false},
+		{`a "b c" 'd e'`, []string{"a", "b c", "d e"}, false},
+		{`a "" b`, []string{"a", "", "b"}, false},
+		{`a\ b "c\"d" 'e\f'`, []string{"a b", `c"d`, `e\f`}, false},
+		{`x="a b"`, []string{"x=a b"}, false},
+		{`a "b`, nil, true},
+		{`a\`, nil, true},
+	}
+	for i, line := range data {
+		actual, err := SplitCommandLine(line.in)
+		ut.AssertEqualIndex(t, i, line.err, err != nil)
+		ut.AssertEqualIndex(t, i, line.expected, actual)
+	}
+}
+
+func TestCheckExamples(t *testing.T) {
+	app := &subcommands.DefaultApplication{
+		Name: "name",
+		Commands: []*subcommands.Command{
+			{
+				UsageLine: "help [<command>]",
+				ShortDesc: subcommands.CmdHelp.ShortDesc,
+				Examples: []subcommands.Example{
+					{Command: "help"},
+					{Command: "help help"},
+					{Command: "help inexistant", ExitCode: 2},
+					{Command: "help a b", ExitCode: 2},
+				},
+				CommandRun: subcommands.CmdHelp.CommandRun,
+			},
+		},
+	}
+	CheckExamples(t, app)
+}
@@ -132,6 +132,17 @@ func (a *ApplicationMock) GetErr() io.Writer {
 	return &a.bufErr
 }
 
+// GetStyle implements subcommands.StyledApplication. It disables styling so
+// the output is deterministic.
+func (a *ApplicationMock) GetStyle() *subcommands.Style {
+	return nil
+}
+
+// Unwrap implements subcommands.ApplicationWrapper.
+func (a *ApplicationMock) Unwrap() subcommands.Application {
+	return a.Application
+}
+
 // MakeAppMock returns an initialized ApplicationMock.
 func MakeAppMock(t *testing.T, a subcommands.Application) *ApplicationMock {
 	return &ApplicationMock{a, MakeTB(t)}
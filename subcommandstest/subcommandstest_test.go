@@ -83,3 +83,23 @@ func TestBadCommand(t *testing.T) {
 	ut.AssertEqual(t, r, 2)
 	a.CheckBuffer(false, true)
 }
+
+func TestAppMockUnwrap(t *testing.T) {
+	app := &subcommands.DefaultApplication{Name: "name"}
+	a := MakeAppMock(t, app)
+	v, ok := subcommands.AppAs[*subcommands.DefaultApplication](a)
+	ut.AssertEqual(t, true, ok)
+	ut.AssertEqual(t, app, v)
+}
+
+func TestAppMockStyle(t *testing.T) {
+	t.Setenv("CLICOLOR_FORCE", "1")
+	app := &subcommands.DefaultApplication{
+		Name:     "name",
+		Commands: []*subcommands.Command{subcommands.CmdHelp},
+	}
+	a := MakeAppMock(t, app)
+	ut.AssertEqual(t, 2, subcommands.Run(a, []string{"non_existing_command"}))
+	a.CheckBuffer(false, true)
+	ut.AssertEqual(t, (*subcommands.Style)(nil), a.GetStyle())
+}
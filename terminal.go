@@ -0,0 +1,150 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ColumnsEnvVar is the environment variable that overrides the width used to
+// wrap help pages. "0" disables wrapping.
+//
+// By default, help pages are wrapped to the width of the terminal, and not
+// wrapped when the output is not a terminal.
+const ColumnsEnvVar = "SUBCOMMANDS_COLUMNS"
+
+// terminalWidth returns the width to use to wrap text written to w, or 0 if
+// the text shouldn't be wrapped.
+func terminalWidth(w io.Writer) int {
+	if v, ok := os.LookupEnv(ColumnsEnvVar); ok {
+		if i, err := strconv.Atoi(v); err == nil && i >= 0 {
+			return i
+		}
+	}
+	f, ok := w.(*os.File)
+	if !ok || !isTerminal(f) {
+		return 0
+	}
+	if width, ok := terminalSize(f); ok {
+		return width
+	}
+	return 80
+}
+
+// isTerminal returns true if f is a character device, which is a good enough
+// approximation of a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// displayWidth returns the number of columns s occupies on a terminal.
+//
+// Combining marks and format characters are zero-width, East Asian wide
+// characters and emojis use two columns. ANSI escape sequences are skipped.
+func displayWidth(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '[' {
+			// Skip the CSI sequence up to the final byte.
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		w += runeWidth(r)
+	}
+	return w
+}
+
+// wideRanges are the East Asian wide and fullwidth ranges, plus the most
+// common emoji blocks.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f},
+	{0x2e80, 0x303e},
+	{0x3041, 0x33ff},
+	{0x3400, 0x4dbf},
+	{0x4e00, 0x9fff},
+	{0xa000, 0xa4cf},
+	{0xac00, 0xd7a3},
+	{0xf900, 0xfaff},
+	{0xfe30, 0xfe4f},
+	{0xff00, 0xff60},
+	{0xffe0, 0xffe6},
+	{0x1f300, 0x1f64f},
+	{0x1f900, 0x1f9ff},
+	{0x20000, 0x2fffd},
+	{0x30000, 0x3fffd},
+}
+
+// runeWidth returns the number of columns r occupies on a terminal.
+func runeWidth(r rune) int {
+	if r < 0x20 || r == 0x7f || r == 0x200b || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	for _, rg := range wideRanges {
+		if r < rg[0] {
+			break
+		}
+		if r <= rg[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// padRight pads s with spaces up to width columns.
+func padRight(s string, width int) string {
+	if n := width - displayWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// wrapText wraps each line of s so it fits in width columns when starting at
+// column indent. Continuation lines are indented with indent spaces. Words
+// longer than the available width are not broken.
+//
+// Text is not wrapped if width is 0.
+func wrapText(s string, width, indent int) string {
+	avail := width - indent
+	if width <= 0 || avail <= 0 {
+		return s
+	}
+	prefix := "\n" + strings.Repeat(" ", indent)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if displayWidth(line) <= avail {
+			continue
+		}
+		b := strings.Builder{}
+		col := 0
+		for j, word := range strings.Split(line, " ") {
+			ww := displayWidth(word)
+			switch {
+			case j == 0:
+			case col+1+ww > avail && col != 0:
+				b.WriteString(prefix)
+				col = 0
+			default:
+				b.WriteByte(' ')
+				col++
+			}
+			b.WriteString(word)
+			col += ww
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, prefix)
+}
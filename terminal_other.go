@@ -0,0 +1,16 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+//go:build !linux && !darwin && !freebsd
+
+package subcommands
+
+import "os"
+
+// terminalSize returns the number of columns of the terminal f.
+//
+// It is not implemented on this platform.
+func terminalSize(f *os.File) (int, bool) {
+	return 0, false
+}
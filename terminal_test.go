@@ -0,0 +1,85 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	data := []struct {
+		s     string
+		width int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"héllo", 5},
+		{"héllo", 5},
+		{"日本語", 6},
+		{"🍺", 2},
+		{"\x1b[1mbold\x1b[0m", 4},
+	}
+	for i, line := range data {
+		ut.AssertEqualIndex(t, i, line.width, displayWidth(line.s))
+	}
+	ut.AssertEqual(t, "日本 |", padRight("日本", 5)+"|")
+	ut.AssertEqual(t, "toolong", padRight("toolong", 2))
+}
+
+func TestWrapText(t *testing.T) {
+	data := []struct {
+		s        string
+		width    int
+		indent   int
+		expected string
+	}{
+		{"a b c", 0, 0, "a b c"},
+		{"a b c", 10, 0, "a b c"},
+		{"aaa bbb ccc", 7, 0, "aaa bbb\nccc"},
+		{"aaa bbb ccc", 10, 3, "aaa bbb\n   ccc"},
+		{"aaa\nbbb ccc ddd", 7, 0, "aaa\nbbb ccc\nddd"},
+		{"verylongword b", 5, 0, "verylongword\nb"},
+		{"日本 日本 日本", 10, 0, "日本 日本\n日本"},
+	}
+	for i, line := range data {
+		ut.AssertEqualIndex(t, i, line.expected, wrapText(line.s, line.width, line.indent))
+	}
+}
+
+func TestUsage_Wrap(t *testing.T) {
+	t.Setenv(ColumnsEnvVar, "30")
+	a := &DefaultApplication{
+		Name: "App",
+		Commands: []*Command{
+			{UsageLine: "héllo", ShortDesc: "says hello to everyone in the room"},
+			{UsageLine: "日本", ShortDesc: "Japan"},
+		},
+		EnvVars: map[string]EnvVarDefinition{
+			"EVAR": {ShortDesc: "Some variable", Default: "yes"},
+		},
+	}
+	buf := bytes.Buffer{}
+	Usage(&buf, a, false)
+	ut.AssertEqual(t, `
+
+Usage:  App [command] [arguments]
+
+Commands:
+  héllo  says hello to
+         everyone in the room
+  日本   Japan
+
+Environment Variables:
+  EVAR  Some variable
+        (Default: "yes")
+
+
+Use "App help [command]" for more information about a command.
+
+`, buf.String())
+}
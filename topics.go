@@ -0,0 +1,96 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"io"
+)
+
+// HelpTopic is a help page that is not a command, for example to document a
+// configuration file format. It is listed by Usage and printed by CmdHelp,
+// e.g. "<app> help config".
+type HelpTopic struct {
+	Name      string
+	ShortDesc string
+	LongDesc  string
+}
+
+// HelpTopicsApplication is implemented by an Application that has help
+// topics.
+type HelpTopicsApplication interface {
+	Application
+	GetHelpTopics() []*HelpTopic
+}
+
+// HelpTopicData is the data used to render a help topic.
+type HelpTopicData struct {
+	App   Application
+	Topic *HelpTopic
+	// Style is the style to use, nil if the output shouldn't be styled.
+	Style *Style
+}
+
+// HelpTopicRenderer can be implemented by a HelpRenderer to render help
+// topics. The default template is used otherwise.
+type HelpTopicRenderer interface {
+	RenderHelpTopic(w io.Writer, d *HelpTopicData) error
+}
+
+// DefaultHelpTopicTemplate is the template used by TemplateRenderer to
+// render HelpTopicData.
+const DefaultHelpTopicTemplate = `{{with .Topic}}{{if .LongDesc}}{{.LongDesc | trim | wrap 0}}{{else}}{{.ShortDesc}}{{end}}{{end}}
+`
+
+// getHelpTopics returns the help topics of a, if any.
+func getHelpTopics(a Application) []*HelpTopic {
+	if h, ok := AppAs[HelpTopicsApplication](a); ok {
+		return h.GetHelpTopics()
+	}
+	return nil
+}
+
+// FindHelpTopic finds a HelpTopic by name and returns it if found.
+func FindHelpTopic(a Application, name string) *HelpTopic {
+	for _, t := range getHelpTopics(a) {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// findNearestHelp heuristically finds the Command or the HelpTopic the user
+// wanted to type, with the application's Matcher like FindNearestCommand.
+// Commands have precedence over help topics with the same name.
+//
+// The help topics are passed to the Matcher as commands named after them, so
+// a name is matched against commands and help topics alike.
+func findNearestHelp(a Application, name string) (*Command, *HelpTopic) {
+	if c := FindCommand(a, name); c != nil && !c.isSection {
+		return c, nil
+	}
+	cmds := runnableCommands(a)
+	topics := map[*Command]*HelpTopic{}
+	for _, t := range getHelpTopics(a) {
+		c := &Command{UsageLine: t.Name}
+		topics[c] = t
+		cmds = append(cmds, c)
+	}
+	c := getMatcher(a).Find(cmds, name)
+	if t := topics[c]; t != nil {
+		return nil, t
+	}
+	return c, nil
+}
+
+// printHelpTopic prints the help topic t to out.
+func printHelpTopic(out io.Writer, a Application, t *HelpTopic) error {
+	d := &HelpTopicData{App: a, Topic: t, Style: styleFor(a, out)}
+	r, ok := helpRendererFor(a).(HelpTopicRenderer)
+	if !ok {
+		r = &TemplateRenderer{}
+	}
+	return r.RenderHelpTopic(out, d)
+}
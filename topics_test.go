@@ -0,0 +1,118 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func topicsApp() *application {
+	return &application{
+		DefaultApplication: DefaultApplication{
+			Name:  "App",
+			Title: "Title",
+			Commands: []*Command{
+				CmdHelp,
+				{UsageLine: "configure", ShortDesc: "configures", CommandRun: func() CommandRun { return &command{} }},
+			},
+			HelpTopics: []*HelpTopic{
+				{Name: "config", ShortDesc: "configuration file format", LongDesc: "The configuration file is JSON.\n"},
+				{Name: "authentication", ShortDesc: "how to authenticate"},
+				{Name: "help", ShortDesc: "shadowed by the command"},
+			},
+		},
+	}
+}
+
+func TestUsage_HelpTopics(t *testing.T) {
+	buf := bytes.Buffer{}
+	Usage(&buf, topicsApp(), false)
+	expected := "Title\n" +
+		"\n" +
+		"Usage:  App [command] [arguments]\n" +
+		"\n" +
+		"Commands:\n" +
+		"  help       prints help about a command\n" +
+		"  configure  configures\n" +
+		"\n" +
+		"Additional help topics:\n" +
+		"  config          configuration file format\n" +
+		"  authentication  how to authenticate\n" +
+		"  help            shadowed by the command\n" +
+		"\n" +
+		"\n" +
+		"Use \"App help [command]\" for more information about a command.\n" +
+		"Use \"App help [topic]\" for more information about that topic.\n" +
+		"\n"
+	ut.AssertEqual(t, expected, buf.String())
+}
+
+func TestCmdHelp_HelpTopics(t *testing.T) {
+	data := []struct {
+		args []string
+		out  string
+		err  string
+		exit int
+	}{
+		{[]string{"help", "config"}, "The configuration file is JSON.\n", "", 0},
+		{[]string{"help", "auth"}, "how to authenticate\n", "", 0},
+		{[]string{"help", "authentification"}, "how to authenticate\n", "", 0},
+		{[]string{"help", "configure"}, "", "usage:  App configure\n", 0},
+		{[]string{"help", "conf"}, "The configuration file is JSON.\n", "", 0},
+		{[]string{"help", "xyz"}, "", "App: unknown command `xyz`\n\nRun 'App help' for usage.\n", 2},
+		{[]string{"help", "help"}, "", "Prints an overview", 0},
+		{[]string{"config"}, "", "App: `config` is a help topic, not a command\n\nRun 'App help config' to read it.\n", 2},
+		{[]string{"auth"}, "", "App: unknown command `auth`\n\nRun 'App help' for usage.\n", 2},
+	}
+	for i, line := range data {
+		line := line
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			a := topicsApp()
+			ut.AssertEqual(t, line.exit, Run(a, line.args))
+			ut.AssertEqual(t, line.out, a.out.String())
+			if !strings.HasPrefix(a.err.String(), line.err) {
+				t.Fatalf("expected prefix %q, got %q", line.err, a.err.String())
+			}
+		})
+	}
+}
+
+func TestFindNearest(t *testing.T) {
+	data := []struct {
+		names    []string
+		name     string
+		expected int
+	}{
+		{nil, "a", -1},
+		{[]string{"foo", "bar"}, "bar", 1},
+		{[]string{"foo", "foo"}, "foo", 0},
+		{[]string{"foo", "foo", "bar"}, "fo", 0},
+		{[]string{"foo", "foo", "bar"}, "FO", 0},
+		{[]string{"longcommand", "longcommand"}, "longcmomand", 0},
+		{[]string{"foo", "fob"}, "fo", -1},
+	}
+	for i, line := range data {
+		ut.AssertEqualIndex(t, i, line.expected, findNearest(line.names, line.name))
+	}
+}
+
+func TestWriteMarkdown_HelpTopics(t *testing.T) {
+	buf := bytes.Buffer{}
+	a := topicsApp()
+	a.Commands = nil
+	a.HelpTopics = a.HelpTopics[:2]
+	ut.AssertEqual(t, nil, WriteMarkdown(&buf, a))
+	ut.AssertEqual(t, "# App\n\nTitle\n\n## Usage\n\n```\nApp [command] [arguments]\n```\n\n## Commands\n\n"+
+		"## Help Topics\n\n### config\n\nThe configuration file is JSON.\n\n### authentication\n\nhow to authenticate\n", buf.String())
+	buf.Reset()
+	ut.AssertEqual(t, nil, WriteMan(&buf, a))
+	ut.AssertEqual(t, ".TH APP 1\n.SH NAME\nApp \\- Title\n.SH SYNOPSIS\n.B App\n[command] [arguments]\n.SH COMMANDS\n"+
+		".SH TOPICS\n.SS config\nThe configuration file is JSON.\n.SS authentication\nhow to authenticate\n", buf.String())
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// ApplicationWrapper is implemented by an Application that wraps another one,
+// for example to mock GetOut and GetErr in unit tests.
+type ApplicationWrapper interface {
+	Application
+	// Unwrap returns the wrapped Application.
+	Unwrap() Application
+}
+
+// AppAs returns the first Application in the chain formed by a and the
+// Applications returned by ApplicationWrapper.Unwrap that is of type T.
+//
+// T is usually the concrete type of the application or an interface.
+func AppAs[T any](a Application) (T, bool) {
+	for a != nil {
+		if t, ok := a.(T); ok {
+			return t, true
+		}
+		w, ok := a.(ApplicationWrapper)
+		if !ok {
+			break
+		}
+		a = w.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
+// TypedCommandRun is like CommandRun except that Run also receives a typed
+// value, either the concrete application or a dependency.
+type TypedCommandRun[T any] interface {
+	// Run executes the command. a is the application as passed to Run, which
+	// should be used for GetOut and GetErr so output can be mocked.
+	Run(a Application, v T, args []string, env Env) int
+
+	// GetFlags returns the flags for this specific command. See
+	// CommandRun.GetFlags.
+	GetFlags() *flag.FlagSet
+}
+
+// AppCommand returns a copy of c with CommandRun set to create the
+// TypedCommandRun returned by newRun. Its Run receives the application as type
+// A, as returned by AppAs.
+//
+// This works when the command is reused in another application, for example a
+// nested one, or when the application is wrapped in a mock.
+//
+// The application type is checked when Run starts, for all the commands of
+// the application, before parsing the command line. Run panics if no
+// application of type A is found, as it is a programming error.
+func AppCommand[A Application](c Command, newRun func() TypedCommandRun[A]) *Command {
+	get := func(a Application) (A, error) {
+		v, ok := AppAs[A](a)
+		if !ok {
+			return v, fmt.Errorf("command %s requires an application of type %s, got %T", c.Name(), reflect.TypeOf((*A)(nil)).Elem(), a)
+		}
+		return v, nil
+	}
+	c.appCheck = func(a Application) error {
+		_, err := get(a)
+		return err
+	}
+	c.CommandRun = func() CommandRun {
+		return &typedRun[A]{r: newRun(), get: get}
+	}
+	return &c
+}
+
+// checkApplication panics if a command of a requires another type of
+// application. See AppCommand.
+func checkApplication(a Application) {
+	for _, c := range a.GetCommands() {
+		if c.appCheck != nil {
+			if err := c.appCheck(a); err != nil {
+				panic("subcommands: " + err.Error())
+			}
+		}
+	}
+}
+
+// DepCommand returns a copy of c with CommandRun set to create the
+// TypedCommandRun returned by newRun. Its Run receives v.
+//
+// This is useful to inject dependencies into commands without relying on
+// the concrete type of the application. Unlike AppCommand, the type is
+// checked at compile time.
+func DepCommand[T any](c Command, v T, newRun func() TypedCommandRun[T]) *Command {
+	c.CommandRun = func() CommandRun {
+		return &typedRun[T]{
+			r: newRun(),
+			get: func(Application) (T, error) {
+				return v, nil
+			},
+		}
+	}
+	return &c
+}
+
+// typedRun adapts a TypedCommandRun into a CommandRun.
+type typedRun[T any] struct {
+	r   TypedCommandRun[T]
+	get func(a Application) (T, error)
+}
+
+func (t *typedRun[T]) GetFlags() *flag.FlagSet {
+	return t.r.GetFlags()
+}
+
+func (t *typedRun[T]) Run(a Application, args []string, env Env) int {
+	v, err := t.get(a)
+	if err != nil {
+		// Only reached when CommandRun is used directly, Run checks the type
+		// first.
+		fmt.Fprintf(a.GetErr(), "%s %s\n", errorPrefix(a), err)
+		return 1
+	}
+	return t.r.Run(a, v, args, env)
+}
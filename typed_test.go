@@ -0,0 +1,113 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+// customApp is an application with an additional field.
+type customApp struct {
+	application
+	greeting string
+}
+
+// wrappedApp wraps an application, like subcommandstest.ApplicationMock does.
+type wrappedApp struct {
+	Application
+	out io.Writer
+}
+
+func (w *wrappedApp) GetOut() io.Writer {
+	return w.out
+}
+
+func (w *wrappedApp) Unwrap() Application {
+	return w.Application
+}
+
+type greetTypedRun struct {
+	CommandRunBase
+}
+
+func (g *greetTypedRun) Run(a Application, c *customApp, args []string, env Env) int {
+	fmt.Fprintf(a.GetOut(), "%s %s\n", c.greeting, args[0])
+	return 0
+}
+
+type depsRun struct {
+	CommandRunBase
+}
+
+func (d *depsRun) Run(a Application, greeting string, args []string, env Env) int {
+	fmt.Fprintf(a.GetOut(), "%s\n", greeting)
+	return 0
+}
+
+func TestAppAs(t *testing.T) {
+	c := &customApp{}
+	w := &wrappedApp{Application: c}
+	v, ok := AppAs[*customApp](w)
+	ut.AssertEqual(t, true, ok)
+	ut.AssertEqual(t, c, v)
+	_, ok = AppAs[*DefaultApplication](w)
+	ut.AssertEqual(t, false, ok)
+	_, ok = AppAs[*customApp](nil)
+	ut.AssertEqual(t, false, ok)
+}
+
+func TestAppCommand(t *testing.T) {
+	cmd := AppCommand(Command{UsageLine: "greet <who>"}, func() TypedCommandRun[*customApp] {
+		return &greetTypedRun{}
+	})
+	c := &customApp{greeting: "Hello"}
+	c.Name = "App"
+	c.Commands = []*Command{cmd}
+	ut.AssertEqual(t, 0, Run(c, []string{"greet", "joe"}))
+	ut.AssertEqual(t, "Hello joe\n", c.out.String())
+
+	// The typed value is found through the wrapper but the output goes through
+	// the wrapper.
+	c.out.Reset()
+	w := &wrappedApp{Application: c, out: &c.err}
+	ut.AssertEqual(t, 0, Run(w, []string{"greet", "joe"}))
+	ut.AssertEqual(t, "", c.out.String())
+	ut.AssertEqual(t, "Hello joe\n", c.err.String())
+
+	// The command is reused in another application, which is a programming
+	// error caught whatever the command run.
+	other := &application{
+		DefaultApplication: DefaultApplication{
+			Name:     "Other",
+			Commands: []*Command{CmdHelp, cmd},
+			Style:    &Style{},
+		},
+	}
+	func() {
+		defer func() {
+			ut.AssertEqual(t, "subcommands: command greet requires an application of type *subcommands.customApp, got *subcommands.application", recover())
+		}()
+		Run(other, []string{"help"})
+		t.Fatal("expected panic")
+	}()
+	ut.AssertEqual(t, "", other.out.String())
+
+	// Used directly, the CommandRun prints an error.
+	ut.AssertEqual(t, 1, cmd.CommandRun().Run(other, []string{"joe"}, nil))
+	ut.AssertEqual(t, "Other: command greet requires an application of type *subcommands.customApp, got *subcommands.application\n", other.err.String())
+}
+
+func TestDepCommand(t *testing.T) {
+	cmd := DepCommand(Command{UsageLine: "hi"}, "Bonjour", func() TypedCommandRun[string] {
+		return &depsRun{}
+	})
+	a := &application{DefaultApplication: DefaultApplication{Commands: []*Command{cmd}}}
+	ut.AssertEqual(t, 0, Run(a, []string{"hi"}))
+	ut.AssertEqual(t, "Bonjour\n", a.out.String())
+}
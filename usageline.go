@@ -0,0 +1,210 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseUsageLine parses the positional arguments described by a
+// Command.UsageLine. The first word, the command name, is skipped.
+//
+// The grammar is the one used by the 'go' tool:
+//
+//   - <name> is a required argument.
+//   - [<name>] is an optional argument.
+//   - a|b are alternatives; literal words are only accepted as is, e.g.
+//     "start|stop".
+//   - <name>... or <name> ... is an argument that can be repeated.
+//   - -flag, -flag <value>, <options> and <flags> describe flags and are
+//     ignored, since flags are parsed by the command's FlagSet.
+//
+// A bare word outside of alternatives, e.g. "FILE" or "[args]", is an error
+// since its meaning is informal. Run doesn't validate the arguments of such
+// usage lines.
+func ParseUsageLine(line string) ([]Arg, error) {
+	tokens, err := splitUsage(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("empty usage line")
+	}
+	return parseUsageTokens(tokens[1:], false, false)
+}
+
+// splitUsage splits s on whitespace and '|' that are not enclosed in brackets.
+// '|' is returned as its own token.
+func splitUsage(s string) ([]string, error) {
+	var tokens []string
+	var stack []rune
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, s[start:end])
+			start = -1
+		}
+	}
+	for i, r := range s {
+		switch r {
+		case '[', '<':
+			stack = append(stack, r)
+		case ']', '>':
+			open := '['
+			if r == '>' {
+				open = '<'
+			}
+			if len(stack) == 0 || stack[len(stack)-1] != open {
+				return nil, fmt.Errorf("unbalanced %q in %q", r, s)
+			}
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 && (r == ' ' || r == '\t' || r == '|') {
+			flush(i)
+			if r == '|' {
+				tokens = append(tokens, "|")
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("unbalanced %q in %q", stack[len(stack)-1], s)
+	}
+	flush(len(s))
+	return tokens, nil
+}
+
+// parseUsageTokens converts the tokens returned by splitUsage into arguments.
+//
+// Bare words are literal choices when literals is true, e.g. the "b" in
+// "a|[b]".
+func parseUsageTokens(tokens []string, optional, literals bool) ([]Arg, error) {
+	var out []Arg
+	for i := 0; i < len(tokens); i++ {
+		// Collect the alternatives.
+		alts := []string{tokens[i]}
+		for i+1 < len(tokens) && tokens[i+1] == "|" {
+			if i+2 == len(tokens) {
+				return nil, errors.New("dangling '|'")
+			}
+			alts = append(alts, tokens[i+2])
+			i += 2
+		}
+		if alts[0] == "|" {
+			return nil, errors.New("dangling '|'")
+		}
+		if alts[0] == "..." {
+			if len(out) == 0 {
+				return nil, errors.New("'...' must follow an argument")
+			}
+			out[len(out)-1].Variadic = true
+			continue
+		}
+		if len(alts) == 1 && isUsageFlag(alts[0]) {
+			// Skip the flag's value if there is one.
+			if strings.HasPrefix(alts[0], "-") && i+1 < len(tokens) && strings.HasPrefix(tokens[i+1], "<") {
+				i++
+			}
+			continue
+		}
+		args, err := parseUsageAlternatives(alts, optional, literals)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, args...)
+	}
+	return out, nil
+}
+
+// parseUsageAlternatives parses one element of the usage line, possibly with
+// alternatives.
+func parseUsageAlternatives(alts []string, optional, literals bool) ([]Arg, error) {
+	var names, choices []string
+	hasFlag := false
+	variadic := false
+	for _, alt := range alts {
+		if strings.HasSuffix(alt, "...") && alt != "..." {
+			variadic = true
+			alt = strings.TrimSuffix(alt, "...")
+		}
+		switch {
+		case isUsageFlag(alt):
+			hasFlag = true
+		case strings.HasPrefix(alt, "["):
+			inner, err := parseOptionalUsage(alt, literals || len(alts) > 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(alts) == 1 {
+				if variadic && len(inner) != 0 {
+					inner[len(inner)-1].Variadic = true
+				}
+				return inner, nil
+			}
+			// Optional element in an alternative, e.g. "a|[b]".
+			optional = true
+			for _, a := range inner {
+				if a.Name != "" {
+					names = append(names, a.Name)
+				}
+				choices = append(choices, a.Choices...)
+			}
+		case strings.HasPrefix(alt, "<"):
+			name := alt[1 : len(alt)-1]
+			if name == "" {
+				return nil, errors.New("empty argument name '<>'")
+			}
+			names = append(names, name)
+		case len(alts) == 1 && !literals:
+			return nil, fmt.Errorf("informal argument %q, use <%s> for a placeholder", alt, strings.ToLower(alt))
+		default:
+			choices = append(choices, alt)
+		}
+	}
+	if len(names) == 0 && len(choices) == 0 {
+		return nil, nil
+	}
+	a := Arg{Optional: optional || hasFlag, Variadic: variadic}
+	if len(names) != 0 {
+		a.Name = strings.Join(names, "|")
+	} else {
+		a.Choices = choices
+	}
+	return []Arg{a}, nil
+}
+
+// parseOptionalUsage parses a "[...]" element.
+func parseOptionalUsage(s string, literals bool) ([]Arg, error) {
+	if !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("unexpected text after ']' in %q", s)
+	}
+	inner := s[1 : len(s)-1]
+	if isUsageFlag(inner) {
+		return nil, nil
+	}
+	tokens, err := splitUsage(inner)
+	if err != nil {
+		return nil, err
+	}
+	return parseUsageTokens(tokens, true, literals)
+}
+
+// isUsageFlag returns true if s describes flags instead of a positional
+// argument.
+func isUsageFlag(s string) bool {
+	if strings.HasPrefix(s, "-") && s != "-" {
+		return true
+	}
+	switch strings.Trim(s, "<>[]") {
+	case "options", "option", "flags", "flag":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func TestParseUsageLine(t *testing.T) {
+	data := []struct {
+		line     string
+		expected []Arg
+	}{
+		{"foo", nil},
+		{"greet <who>", []Arg{{Name: "who"}}},
+		{"sleep <options>", nil},
+		{"sleep [flags]", nil},
+		{"cp <src>... <dst>", []Arg{{Name: "src", Variadic: true}, {Name: "dst"}}},
+		{"cat [<file> ...]", []Arg{{Name: "file", Optional: true, Variadic: true}}},
+		{"cat [<file>]...", []Arg{{Name: "file", Optional: true, Variadic: true}}},
+		{"help [<command>|-advanced]", []Arg{{Name: "command", Optional: true}}},
+		{"svc start|stop <name>", []Arg{{Choices: []string{"start", "stop"}}, {Name: "name"}}},
+		{"get -o <format> <key>", []Arg{{Name: "key"}}},
+		{"get [-o <format>] <key>", []Arg{{Name: "key"}}},
+		{"find <path>|<id>", []Arg{{Name: "path|id"}}},
+		{"run <file name>", []Arg{{Name: "file name"}}},
+		{"svc [start|stop]", []Arg{{Optional: true, Choices: []string{"start", "stop"}}}},
+		{"svc start|[stop]", []Arg{{Optional: true, Choices: []string{"start", "stop"}}}},
+		{"ask <subcommand> [<args>...]", []Arg{{Name: "subcommand"}, {Name: "args", Optional: true, Variadic: true}}},
+		{"describe <command>", []Arg{{Name: "command"}}},
+	}
+	for i, line := range data {
+		actual, err := ParseUsageLine(line.line)
+		ut.AssertEqualIndex(t, i, nil, err)
+		ut.AssertEqualIndex(t, i, line.expected, actual)
+	}
+}
+
+func TestParseUsageLine_Error(t *testing.T) {
+	data := []string{
+		"",
+		"foo <bar",
+		"foo bar>",
+		"foo [<bar>",
+		"foo <>",
+		"foo ...",
+		"foo a |",
+		// Informal usage lines.
+		"build FILE",
+		"run <file> [args]",
+		"tool [flags] [packages]",
+	}
+	for i, line := range data {
+		_, err := ParseUsageLine(line)
+		ut.AssertEqualIndex(t, i, true, err != nil)
+	}
+}
+
+func TestRunUsageLine(t *testing.T) {
+	data := []struct {
+		args []string
+		err  string
+		exit int
+	}{
+		{[]string{"svc", "start", "db"}, "", 42},
+		{[]string{"svc", "restart", "db"}, "App: invalid value \"restart\" for argument start|stop: must be one of start, stop\n\nRun 'App help svc' for usage.\n", 2},
+		{[]string{"svc", "start"}, "App: missing argument <name>\n\nRun 'App help svc' for usage.\n", 2},
+		{[]string{"free", "a", "b"}, "", 42},
+		{[]string{"loose", "a", "b"}, "", 42},
+		{[]string{"help", "svc"}, "usage:  App svc start|stop <name>\n  start|stop\n  <name>\n", 0},
+		{[]string{"run", "f", "x"}, "", 42},
+		{[]string{"build", "main.go"}, "", 42},
+		{[]string{"tool", "./...", "./x"}, "", 42},
+		{[]string{"ask", "apple", "-direct", "x"}, "", 42},
+		{[]string{"ask"}, "App: missing argument <subcommand>\n\nRun 'App help ask' for usage.\n", 2},
+		{[]string{"describe", "a"}, "", 42},
+		{[]string{"describe", "a", "b"}, "App: unexpected argument \"b\"\n\nRun 'App help describe' for usage.\n", 2},
+	}
+	for i, line := range data {
+		line := line
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			a := application{
+				DefaultApplication: DefaultApplication{
+					Name: "App",
+					Commands: []*Command{
+						CmdHelp,
+						{
+							UsageLine:  "svc start|stop <name>",
+							CommandRun: func() CommandRun { return &command{} },
+						},
+						{
+							UsageLine:  "free <one>",
+							FreeForm:   true,
+							CommandRun: func() CommandRun { return &command{} },
+						},
+						{
+							// Not parseable, so not validated.
+							UsageLine:  "loose <one",
+							CommandRun: func() CommandRun { return &command{} },
+						},
+						// Informal usage lines are not validated.
+						{UsageLine: "run <file> [args]", CommandRun: func() CommandRun { return &command{} }},
+						{UsageLine: "build FILE", CommandRun: func() CommandRun { return &command{} }},
+						{UsageLine: "describe <command>", CommandRun: func() CommandRun { return &command{} }},
+						{UsageLine: "tool [flags] [packages]", CommandRun: func() CommandRun { return &command{} }},
+						{
+							// The arguments after the subcommand are parsed by a nested
+							// application.
+							UsageLine: "ask <subcommand> [<args>...]",
+							CommandRun: func() CommandRun {
+								c := &command{}
+								c.Flags.Bool("v", false, "")
+								return c
+							},
+						},
+					},
+				},
+			}
+			ut.AssertEqual(t, line.exit, Run(&a, line.args))
+			ut.AssertEqual(t, line.err, a.err.String())
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Version information reported by CmdVersion. When set, Version and Revision
+// override the values read from the build information embedded by the Go
+// toolchain. BuildTime is only known when set, as the toolchain doesn't embed
+// it.
+//
+// Set them at link time, e.g.:
+//
+//	go build -ldflags "-X github.com/maruel/subcommands.Version=v1.2.3"
+var (
+	Version   string
+	Revision  string
+	BuildTime string
+)
+
+// VersionInfo describes the version of the running executable.
+//
+// CommitTime is the time of the commit of Revision. BuildTime is only set via
+// the BuildTime variable.
+type VersionInfo struct {
+	Module     string `json:"module,omitempty"`
+	Version    string `json:"version"`
+	Revision   string `json:"revision,omitempty"`
+	Dirty      bool   `json:"dirty,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	BuildTime  string `json:"build_time,omitempty"`
+	GoVersion  string `json:"go_version"`
+}
+
+// GetVersionInfo returns the version of the running executable, as embedded
+// by the Go toolchain with the overrides in Version, Revision and BuildTime.
+func GetVersionInfo() VersionInfo {
+	bi, _ := debug.ReadBuildInfo()
+	v := versionInfo(bi)
+	if Version != "" {
+		v.Version = Version
+	}
+	if Revision != "" {
+		v.Revision = Revision
+		v.Dirty = false
+		// The commit time embedded by the toolchain may be of another revision.
+		v.CommitTime = ""
+	}
+	if BuildTime != "" {
+		v.BuildTime = BuildTime
+	}
+	return v
+}
+
+// versionInfo extracts the version from the build information.
+func versionInfo(bi *debug.BuildInfo) VersionInfo {
+	v := VersionInfo{Version: "(devel)", GoVersion: runtime.Version()}
+	if bi == nil {
+		return v
+	}
+	v.Module = bi.Main.Path
+	if bi.Main.Version != "" {
+		v.Version = bi.Main.Version
+	}
+	if bi.GoVersion != "" {
+		v.GoVersion = bi.GoVersion
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			v.Revision = s.Value
+		case "vcs.time":
+			v.CommitTime = s.Value
+		case "vcs.modified":
+			v.Dirty = s.Value == "true"
+		}
+	}
+	return v
+}
+
+// CmdVersion defines the version command. It can be included in your
+// application's Commands list.
+//
+// When included, "<tool> -version" is an alias for "<tool> version".
+var CmdVersion = &Command{
+	UsageLine: "version <options>",
+	ShortDesc: "prints version information",
+	LongDesc:  "Prints the version, the revision, the commit time and the build time of the tool.",
+	CommandRun: func() CommandRun {
+		ret := &versionRun{}
+		ret.Flags.BoolVar(&ret.json, "json", false, "print as JSON")
+		return ret
+	},
+}
+
+type versionRun struct {
+	CommandRunBase
+	json bool
+}
+
+func (c *versionRun) Run(a Application, args []string, env Env) int {
+	v := GetVersionInfo()
+	if c.json {
+		b, _ := json.MarshalIndent(v, "", "  ")
+		fmt.Fprintf(a.GetOut(), "%s\n", b)
+		return 0
+	}
+	fmt.Fprintf(a.GetOut(), "%s version %s\n", a.GetName(), v.Version)
+	if v.Revision != "" {
+		dirty := ""
+		if v.Dirty {
+			dirty = " (dirty)"
+		}
+		fmt.Fprintf(a.GetOut(), "revision:  %s%s\n", v.Revision, dirty)
+	}
+	if v.CommitTime != "" {
+		fmt.Fprintf(a.GetOut(), "committed: %s\n", v.CommitTime)
+	}
+	if v.BuildTime != "" {
+		fmt.Fprintf(a.GetOut(), "built:     %s\n", v.BuildTime)
+	}
+	fmt.Fprintf(a.GetOut(), "go:        %s\n", v.GoVersion)
+	return 0
+}
+
+// isVersionFlag returns true if arg is a top-level -version flag and the
+// application supports the version command.
+func isVersionFlag(a Application, arg string) bool {
+	return (arg == "-version" || arg == "--version") && FindCommand(a, "version") != nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"encoding/json"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func TestVersionInfo(t *testing.T) {
+	ut.AssertEqual(t, VersionInfo{Version: "(devel)", GoVersion: runtime.Version()}, versionInfo(nil))
+	bi := &debug.BuildInfo{
+		GoVersion: "go1.21.0",
+		Main:      debug.Module{Path: "example.com/tool", Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "abc123"},
+			{Key: "vcs.time", Value: "2024-01-02T03:04:05Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+	expected := VersionInfo{
+		Module:     "example.com/tool",
+		Version:    "v1.2.3",
+		Revision:   "abc123",
+		Dirty:      true,
+		CommitTime: "2024-01-02T03:04:05Z",
+		GoVersion:  "go1.21.0",
+	}
+	ut.AssertEqual(t, expected, versionInfo(bi))
+}
+
+func TestCmdVersion(t *testing.T) {
+	for _, args := range [][]string{{"version"}, {"-version"}, {"--version"}} {
+		a := &application{DefaultApplication: DefaultApplication{Name: "App", Commands: []*Command{CmdVersion}}}
+		ut.AssertEqual(t, 0, Run(a, args))
+		ut.AssertEqual(t, true, strings.HasPrefix(a.out.String(), "App version "))
+		ut.AssertEqual(t, true, strings.Contains(a.out.String(), "go:        "))
+	}
+
+	a := &application{DefaultApplication: DefaultApplication{Name: "App", Commands: []*Command{CmdVersion}}}
+	ut.AssertEqual(t, 0, Run(a, []string{"-version", "-json"}))
+	v := VersionInfo{}
+	ut.AssertEqual(t, nil, json.Unmarshal(a.out.Bytes(), &v))
+	ut.AssertEqual(t, GetVersionInfo(), v)
+
+	// -version is not special without CmdVersion.
+	a = &application{DefaultApplication: DefaultApplication{Name: "App", Commands: []*Command{CmdHelp}}}
+	ut.AssertEqual(t, 2, Run(a, []string{"-version"}))
+}
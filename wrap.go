@@ -0,0 +1,72 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"flag"
+	"sort"
+)
+
+// Wrapper describes the program run by a command created by WrapCommand.
+type Wrapper struct {
+	// Program is the executable to run. It is searched in PATH if it contains
+	// no path separator.
+	Program string
+	// Args are passed to Program before the command line arguments.
+	Args []string
+	// ForwardEnv sets the application's environment variables that are not set
+	// to their default value in the environment of Program.
+	ForwardEnv bool
+	// Env returns environment variables to add to the environment of Program,
+	// in the form "KEY=value". It receives the resolved environment variables
+	// of the application. It is optional.
+	Env func(env Env) []string
+}
+
+// WrapCommand returns a copy of c with CommandRun set to run the program
+// described by w with the command line arguments unchanged, as the command
+// has no flags.
+//
+// The program is connected to os.Stdin and to the application's GetOut and
+// GetErr. SIGINT and SIGTERM are forwarded to it, except SIGINT sent by
+// Ctrl-C, which the terminal already sends to the program. Its exit code is
+// returned by Run. When it is killed by a signal, Run returns 128 plus the
+// signal number, like shells do.
+//
+// FreeForm is set unless c.Args is set.
+func WrapCommand(c Command, w Wrapper) *Command {
+	if c.Args == nil {
+		c.FreeForm = true
+	}
+	c.CommandRun = func() CommandRun {
+		return &wrapRun{w: w}
+	}
+	return &c
+}
+
+// wrapRun runs the program of a Wrapper.
+type wrapRun struct {
+	w Wrapper
+}
+
+func (r *wrapRun) Run(a Application, args []string, env Env) int {
+	var vars []string
+	if r.w.ForwardEnv {
+		for k, v := range env {
+			if !v.Exists && v.Value != "" {
+				vars = append(vars, k+"="+v.Value)
+			}
+		}
+		sort.Strings(vars)
+	}
+	if r.w.Env != nil {
+		vars = append(vars, r.w.Env(env)...)
+	}
+	return runChild(a, r.w.Program, append(r.w.Args[:len(r.w.Args):len(r.w.Args)], args...), vars)
+}
+
+func (r *wrapRun) GetFlags() *flag.FlagSet {
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Marc-Antoine Ruel. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package subcommands
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/maruel/ut"
+)
+
+func TestWrapCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	dir := t.TempDir()
+	writeScript(t, dir, "wrapped", `echo "args: $*"
+echo "env: $GREET_STYLE $WRAP_EXTRA" >&2
+exit $EXIT_CODE
+`)
+	c := WrapCommand(Command{UsageLine: "wrap", ShortDesc: "wraps"}, Wrapper{
+		Program:    filepath.Join(dir, "wrapped"),
+		Args:       []string{"-pre"},
+		ForwardEnv: true,
+		Env: func(env Env) []string {
+			return []string{"WRAP_EXTRA=" + env["GREET_STYLE"].Value + "!"}
+		},
+	})
+	ut.AssertEqual(t, true, c.FreeForm)
+	data := []struct {
+		env  string
+		exit string
+		code int
+		err  string
+	}{
+		{"", "0", 0, "env: Hi Hi!\n"},
+		{"Hello", "3", 3, "env: Hello Hello!\n"},
+	}
+	for i, line := range data {
+		if line.env != "" {
+			t.Setenv("GREET_STYLE", line.env)
+		}
+		t.Setenv("EXIT_CODE", line.exit)
+		a := &application{
+			DefaultApplication: DefaultApplication{
+				Name:     "App",
+				Commands: []*Command{c},
+				EnvVars:  map[string]EnvVarDefinition{"GREET_STYLE": {Default: "Hi"}},
+			},
+		}
+		ut.AssertEqualIndex(t, i, line.code, Run(a, []string{"wrap", "-flag", "--", "a b"}))
+		ut.AssertEqualIndex(t, i, "args: -pre -flag -- a b\n", a.out.String())
+		ut.AssertEqualIndex(t, i, line.err, a.err.String())
+	}
+}
+
+func TestWrapCommandMissing(t *testing.T) {
+	c := WrapCommand(Command{UsageLine: "wrap"}, Wrapper{Program: filepath.Join(t.TempDir(), "missing")})
+	a := &application{DefaultApplication: DefaultApplication{Name: "App", Commands: []*Command{c}}}
+	ut.AssertEqual(t, 1, Run(a, []string{"wrap"}))
+	ut.AssertEqual(t, true, a.err.Len() != 0)
+}